@@ -1,99 +1,221 @@
-package main
+package graphalgo
+
+import (
+	"context"
+	"time"
+)
 
 // Astar algorithm
-type Astar struct {
-	graph  *graph
-	source int
-	target int
-	hFn    func(nd1, nd2 int) float32 // heuristic
-
-	frontier map[int]graphEdge // search frontier
-	gcost    map[int]float32   // cost to some node
-	fcost    map[int]float32   // cost to target. fcost = gcost + hcost (heuristic)
-	spt      map[int]graphEdge // shortest path tree
-
-	err error
+type Astar[K comparable, W Weight] struct {
+	graph  *Graph[K, W]
+	source K
+	target K
+	hFn    func(nd1, nd2 K) W // heuristic
+
+	// admissible is true if hFn is consistent, see SetAdmissible,
+	// then the path found is the shortest one.
+	admissible bool
+
+	frontier map[K]Edge[K, W] // search frontier
+	gcost    map[K]W          // cost to some node
+	fcost    map[K]W          // cost to target. fcost = gcost + hcost (heuristic)
+	spt      map[K]Edge[K, W] // shortest path tree
+
+	costFn func(e Edge[K, W]) (W, bool) // nil means e.Cost
+
+	pq    *IndexedPriorityQueueMin[K, W] // kept between steps, nil before the first step
+	state StepState
+
+	err   error
+	stats SearchStats
 }
 
 // NewAstar returns a instance of Dijkstra.
-func NewAstar(g *graph, s, t int) *Astar {
-	return &Astar{
-		graph:    g,
-		source:   s,
-		target:   t,
-		hFn:      func(nd1, nd2 int) float32 { return 0 },
-		frontier: make(map[int]graphEdge),
-		fcost:    make(map[int]float32),
-		gcost:    make(map[int]float32),
-		spt:      make(map[int]graphEdge),
+func NewAstar[K comparable, W Weight](g *Graph[K, W], s, t K) *Astar[K, W] {
+	d := &Astar[K, W]{
+		graph:      g,
+		hFn:        func(nd1, nd2 K) W { return 0 },
+		admissible: true,
 	}
+	d.Reset(s, t)
+	return d
 }
 
 // NewAstarWithH returns a instance of Dijkstra.
-func NewAstarWithH(g *graph, s, t int, h func(nd1, nd2 int) float32) *Astar {
-	return &Astar{
-		graph:    g,
-		source:   s,
-		target:   t,
-		hFn:      h,
-		frontier: make(map[int]graphEdge),
-		fcost:    make(map[int]float32),
-		gcost:    make(map[int]float32),
-		spt:      make(map[int]graphEdge),
+// h is unknown to Astar, so the path found is not marked as optimal.
+func NewAstarWithH[K comparable, W Weight](g *Graph[K, W], s, t K, h func(nd1, nd2 K) W) *Astar[K, W] {
+	d := &Astar[K, W]{
+		graph: g,
+		hFn:   h,
 	}
+	d.Reset(s, t)
+	return d
+}
+
+// SetAdmissible tells Astar whether its heuristic is consistent, that is
+// h(a, t) <= cost(a, b) + h(b, t) for every edge from a to b, which also
+// means it never overestimates. Astar never expands a node twice, so a
+// heuristic which only never overestimates is not enough: the path found
+// is marked as optimal only if admissible is true. It is false for the
+// heuristic given to NewAstarWithH until set. The built-in heuristics
+// documented as admissible are consistent on the same graphs.
+func (d *Astar[K, W]) SetAdmissible(admissible bool) {
+	d.admissible = admissible
+}
+
+// SetCostFunc makes the search walk edge e with cost fn(e) instead of
+// e.Cost. If fn returns false, e can not be walked. fn may read the
+// attributes of e, and of its nodes by Graph.NodeByID.
+func (d *Astar[K, W]) SetCostFunc(fn func(e Edge[K, W]) (W, bool)) {
+	d.costFn = fn
+}
+
+// Algorithm returns AlgorithmAstar.
+func (d *Astar[K, W]) Algorithm() Algorithm {
+	return AlgorithmAstar
+}
+
+// Reset forgets the last search and sets a new query.
+func (d *Astar[K, W]) Reset(s, t K) {
+	d.source = s
+	d.target = t
+	d.frontier = make(map[K]Edge[K, W])
+	d.gcost = make(map[K]W)
+	d.fcost = make(map[K]W)
+	d.spt = make(map[K]Edge[K, W])
+	d.pq = nil
+	d.state = StepSearching
+	d.err = nil
+	d.stats = SearchStats{}
+}
+
+// Stats returns what the last Search did.
+func (d *Astar[K, W]) Stats() SearchStats {
+	return d.stats
 }
 
 // Search trys to find the shortest path from source to target.
-// source is a node index, same as target.
-func (d *Astar) Search() {
-	d.frontier[d.source] = graphEdge{From: d.source, To: d.source}
-	d.gcost[d.source] = 0
-	d.fcost[d.source] = 0
-	pq := NewIndexedPriorityQueueMin(d.fcost)
-	pq.Insert(d.source)
-
-	for !pq.IsEmpty() {
-		idx, err := pq.Pop()
-		if err != nil {
+// source is a node ID, same as target.
+func (d *Astar[K, W]) Search() {
+	d.SearchContext(context.Background())
+}
+
+// SearchContext is Search which stops when ctx is done.
+func (d *Astar[K, W]) SearchContext(ctx context.Context) {
+	for {
+		if err := checkContext(ctx); err != nil {
 			d.err = err
+			d.state = StepFailed
+			return
+		}
+		if d.Step(1) != StepSearching {
 			return
 		}
+	}
+}
+
+// Step expands at most maxExpansions nodes, then returns the state of the search.
+// maxExpansions <= 0 means no limit. The search goes on where it stopped
+// when Step is called again.
+func (d *Astar[K, W]) Step(maxExpansions int) StepState {
+	if d.state != StepSearching {
+		return d.state
+	}
+
+	start := time.Now()
+	defer func() {
+		d.stats.Reached = len(d.frontier)
+		d.stats.Elapsed += time.Since(start)
+	}()
+
+	if d.pq == nil {
+		if !d.graph.HasNode(d.source) || !d.graph.HasNode(d.target) {
+			d.err = ErrInvalidNodeIndex
+			d.state = StepFailed
+			return d.state
+		}
+
+		d.frontier[d.source] = Edge[K, W]{From: d.source, To: d.source}
+		d.gcost[d.source] = 0
+		d.fcost[d.source] = 0
+		d.pq = NewIndexedPriorityQueueMin(d.fcost)
+		d.pq.Insert(d.source)
+	}
+
+	for n := 0; maxExpansions <= 0 || n < maxExpansions; n++ {
+		if d.pq.IsEmpty() {
+			d.state = StepFailed
+			return d.state
+		}
+
+		idx, err := d.pq.Pop()
+		if err != nil {
+			d.err = err
+			d.state = StepFailed
+			return d.state
+		}
 
 		edge := d.frontier[idx]
 		d.spt[idx] = edge
+		d.stats.Expanded++
 		i := edge.To
 
 		if i == d.target {
-			return
+			d.state = StepFound
+			return d.state
 		}
 
-		for _, e := range d.graph.edges[i] {
+		for _, e := range d.graph.EdgesFrom(i) {
+			c, ok := d.edgeCost(e)
+			if !ok {
+				continue
+			}
 			t := e.To
-			g := d.gcost[i] + e.Cost
+			g := AddWeight(d.gcost[i], c)
 			if _, ok := d.frontier[t]; !ok {
 				d.frontier[t] = e
 				d.gcost[t] = g
-				d.fcost[t] = g + d.hFn(t, d.target)
-				pq.Insert(t)
+				d.fcost[t] = AddWeight(g, d.hFn(t, d.target))
+				d.pq.Insert(t)
 			} else if g < d.gcost[t] {
 				if _, ok := d.spt[t]; !ok {
 					d.frontier[t] = e
 					d.gcost[t] = g
-					d.fcost[t] = g + d.hFn(t, d.target)
-					pq.ChangePriority(t)
+					d.fcost[t] = AddWeight(g, d.hFn(t, d.target))
+					d.pq.ChangePriority(t)
 				}
 			}
 		}
 	}
+
+	return d.state
+}
+
+// Explored returns the nodes which have been expanded, in the order of
+// their dense indices.
+func (d *Astar[K, W]) Explored() []K {
+	return exploredNodes(d.graph, d.spt)
+}
+
+// Frontier returns the nodes which have been reached but not expanded yet,
+// in the order of their dense indices.
+func (d *Astar[K, W]) Frontier() []K {
+	return frontierNodes(d.graph, d.frontier, d.spt)
 }
 
 // PathToTarget returns shortest path from source to target.
-func (d *Astar) PathToTarget() ([]graphEdge, error) {
+func (d *Astar[K, W]) PathToTarget() (Path[K, W], error) {
 	if d.err != nil {
-		return []graphEdge{}, d.err
+		return Path[K, W]{}, d.err
+	}
+	if d.state == StepSearching {
+		return Path[K, W]{}, ErrSearchIncomplete
+	}
+	if _, ok := d.spt[d.target]; !ok {
+		return Path[K, W]{}, ErrPathNotFound
 	}
 
-	var path []graphEdge
+	var path []Edge[K, W]
 	idx := d.target
 	for {
 		if idx == d.source {
@@ -107,5 +229,14 @@ func (d *Astar) PathToTarget() ([]graphEdge, error) {
 		idx = e.From
 	}
 
-	return reversePath(path), nil
+	p := NewPath(d.source, reversePath(path), AlgorithmAstar, d.admissible)
+	p.Cost = d.gcost[d.target] // it differs from the sum of e.Cost with a cost function
+	return p, nil
+}
+
+func (d *Astar[K, W]) edgeCost(e Edge[K, W]) (W, bool) {
+	if d.costFn == nil {
+		return e.Cost, true
+	}
+	return d.costFn(e)
 }
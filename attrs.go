@@ -0,0 +1,35 @@
+package graphalgo
+
+// Attrs is a bag of named properties of a node or an edge,
+// such as terrain type or door flags.
+// Read values with Attr, which checks their type.
+type Attrs map[string]interface{}
+
+// Attr returns the value of key in a if it is of type T.
+func Attr[T any](a Attrs, key string) (T, bool) {
+	v, ok := a[key].(T)
+	return v, ok
+}
+
+// With returns a copy of a with key set to value. a is not changed,
+// so nodes and edges copied from each other never share properties.
+func (a Attrs) With(key string, value interface{}) Attrs {
+	b := make(Attrs, len(a)+1)
+	for k, v := range a {
+		b[k] = v
+	}
+	b[key] = value
+	return b
+}
+
+// Clone returns a copy of a.
+func (a Attrs) Clone() Attrs {
+	if a == nil {
+		return nil
+	}
+	b := make(Attrs, len(a))
+	for k, v := range a {
+		b[k] = v
+	}
+	return b
+}
@@ -0,0 +1,120 @@
+package graphalgo
+
+import (
+	"context"
+	"github.com/ZhangGuangxu/circularqueue"
+	"time"
+)
+
+// BFS is breadth first search. It uses a queue as its core data architecture.
+type BFS[K comparable, W Weight] struct {
+	graph  *Graph[K, W]
+	source K
+	target K
+
+	path  Path[K, W]
+	err   error
+	stats SearchStats
+}
+
+// NewBFS returns an instance of BFS.
+func NewBFS[K comparable, W Weight](g *Graph[K, W], s, t K) *BFS[K, W] {
+	d := &BFS[K, W]{graph: g}
+	d.Reset(s, t)
+	return d
+}
+
+// Algorithm returns AlgorithmBFS.
+func (d *BFS[K, W]) Algorithm() Algorithm {
+	return AlgorithmBFS
+}
+
+// Reset forgets the last search and sets a new query.
+func (d *BFS[K, W]) Reset(s, t K) {
+	d.source = s
+	d.target = t
+	d.path = Path[K, W]{}
+	d.err = ErrSearchIncomplete
+	d.stats = SearchStats{}
+}
+
+// Search trys to find a path from source to target.
+func (d *BFS[K, W]) Search() {
+	d.SearchContext(context.Background())
+}
+
+// SearchContext is Search which stops when ctx is done.
+func (d *BFS[K, W]) SearchContext(ctx context.Context) {
+	start := time.Now()
+	d.path, d.err = d.search(ctx)
+	d.stats.Elapsed = time.Since(start)
+}
+
+// PathToTarget returns the path found by Search.
+func (d *BFS[K, W]) PathToTarget() (Path[K, W], error) {
+	return d.path, d.err
+}
+
+// Stats returns what the last Search did.
+func (d *BFS[K, W]) Stats() SearchStats {
+	return d.stats
+}
+
+func (d *BFS[K, W]) search(ctx context.Context) (Path[K, W], error) {
+	b := d.source
+	e := d.target
+	if !d.graph.HasNode(b) || !d.graph.HasNode(e) {
+		return Path[K, W]{}, ErrInvalidNodeIndex
+	}
+
+	if b == e {
+		return NewPath[K, W](b, nil, AlgorithmBFS, false), nil
+	}
+
+	q := circularqueue.NewCircularQueue()
+	for _, tmp := range d.graph.EdgesFrom(b) {
+		q.Push(tmp)
+	}
+	record := make(map[K]Edge[K, W])
+	record[b] = Edge[K, W]{From: b, To: b}
+	d.stats.Expanded = 1
+	defer func() { d.stats.Reached = len(record) }()
+
+	for !q.IsEmpty() {
+		if err := checkContext(ctx); err != nil {
+			return Path[K, W]{}, err
+		}
+
+		tmp, err := q.Pop()
+		if err != nil {
+			return Path[K, W]{}, err
+		}
+		edge, ok := tmp.(Edge[K, W])
+		if !ok {
+			return Path[K, W]{}, ErrEdgeTypeWrong
+		}
+
+		if edge.To == e {
+			var path []Edge[K, W]
+			for {
+				path = append(path, edge)
+				if edge.From == b {
+					return NewPath(b, reversePath(path), AlgorithmBFS, false), nil
+				}
+
+				edge = record[edge.From]
+			}
+		}
+
+		if _, ok := record[edge.To]; ok {
+			continue
+		}
+		for _, tmp := range d.graph.EdgesFrom(edge.To) {
+			q.Push(tmp)
+		}
+		d.stats.Expanded++
+		record[edge.To] = edge
+	}
+
+	return Path[K, W]{}, ErrPathNotFound
+}
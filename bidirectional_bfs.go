@@ -1,94 +1,152 @@
-package main
+package graphalgo
 
 import (
+	"context"
 	"github.com/ZhangGuangxu/circularqueue"
-	"log"
 	"sync"
+	"time"
 )
 
-type biBFS struct {
-	graph  *graph
-	source graphNode
-	target graphNode
+// BiBFS is bidirectional breadth first search.
+// One goroutine searches from source, another one searches from target,
+// they stop when they meet each other.
+// The search from target walks the outgoing edges too, so the graph
+// must be non-directional.
+type BiBFS[K comparable, W Weight] struct {
+	graph  *Graph[K, W]
+	source K
+	target K
 
 	wg *sync.WaitGroup
-	s  *bfs
-	rs *rbfs
+	s  *bfs[K, W]
+	rs *rbfs[K, W]
 
-	stop  chan bool
-	index int
+	ctx    context.Context
+	stop   chan bool
+	index  K // the node where the two searches met
+	joined bool
+
+	path  Path[K, W]
+	err   error
+	stats SearchStats
 }
 
-func newBiBFS(graph *graph, source graphNode, target graphNode) *biBFS {
-	s := &biBFS{
-		graph:  graph,
-		source: source,
-		target: target,
-		wg:     &sync.WaitGroup{},
-		stop:   make(chan bool),
-		index:  invalidNodeIndex,
-	}
+// NewBiBFS returns an instance of BiBFS.
+func NewBiBFS[K comparable, W Weight](graph *Graph[K, W], source K, target K) *BiBFS[K, W] {
+	s := &BiBFS[K, W]{graph: graph}
+	s.Reset(source, target)
+	return s
+}
+
+// Algorithm returns AlgorithmBiBFS.
+func (s *BiBFS[K, W]) Algorithm() Algorithm {
+	return AlgorithmBiBFS
+}
+
+// Reset forgets the last search and sets a new query.
+func (s *BiBFS[K, W]) Reset(source, target K) {
+	s.source = source
+	s.target = target
+	s.begin()
+	s.path = Path[K, W]{}
+	s.err = ErrSearchIncomplete
+	s.stats = SearchStats{}
+}
+
+// begin makes the state of a new search, so Search may run again.
+func (s *BiBFS[K, W]) begin() {
+	var zero K
+	s.wg = &sync.WaitGroup{}
+	s.stop = make(chan bool)
+	s.index = zero
+	s.joined = false
 	s.s = newBFS(s)
 	s.rs = newRBFS(s)
-	return s
 }
 
-func (s *biBFS) search() ([]graphEdge, error) {
-	b := s.source.Index
-	e := s.target.Index
-	if b == e {
-		return []graphEdge{}, nil
+// Search trys to find a path from source to target.
+func (s *BiBFS[K, W]) Search() {
+	s.SearchContext(context.Background())
+}
+
+// SearchContext is Search which stops both goroutines when ctx is done.
+func (s *BiBFS[K, W]) SearchContext(ctx context.Context) {
+	start := time.Now()
+	s.begin()
+	s.ctx = ctx
+	s.path, s.err = s.search()
+	s.stats = SearchStats{
+		Expanded: s.s.expanded + s.rs.expanded,
+		Reached:  len(s.s.record) + len(s.rs.record),
+		Elapsed:  time.Since(start),
+	}
+}
+
+// PathToTarget returns the path found by Search.
+func (s *BiBFS[K, W]) PathToTarget() (Path[K, W], error) {
+	return s.path, s.err
+}
+
+// Stats returns what the last Search did.
+func (s *BiBFS[K, W]) Stats() SearchStats {
+	return s.stats
+}
+
+func (s *BiBFS[K, W]) search() (Path[K, W], error) {
+	if s.source == s.target {
+		return NewPath[K, W](s.source, nil, AlgorithmBiBFS, false), nil
 	}
 
 	s.s.start()
 	s.rs.start()
 	s.wg.Wait()
 
-	if s.index == invalidNodeIndex {
+	if !s.joined {
 		path, err := s.s.result()
 		if err != nil {
-			log.Println(err)
-
-			path, err = s.rs.result()
+			path, err = s.rs.result() // the search from target may have walked to source
 			if err != nil {
-				log.Println(err)
-				return nil, err
+				return Path[K, W]{}, err
 			}
 
-			return path, nil
+			return NewPath(s.source, path, AlgorithmBiBFS, false), nil
 		}
 
-		return path, nil
+		return NewPath(s.source, path, AlgorithmBiBFS, false), nil
 	}
 
 	path1, err := s.s.result()
 	if err != nil {
-		log.Println(err)
-		return nil, err
+		return Path[K, W]{}, err
 	}
 	path2, err := s.rs.result()
 	if err != nil {
-		log.Println(err)
-		return nil, err
+		return Path[K, W]{}, err
 	}
-	log.Printf("%v, %v\n", path1, path2)
 	len1 := len(path1)
-	path := make([]graphEdge, len1+len(path2))
+	path := make([]Edge[K, W], len1+len(path2))
 	copy(path, path1)
 	copy(path[len1:], path2)
-	return path, nil
+	return NewPath(s.source, path, AlgorithmBiBFS, false), nil
 }
 
-func (s *biBFS) checkJoin(idx int) bool {
+// Join returns the node where the two searches met.
+// The bool is false if they did not meet.
+func (s *BiBFS[K, W]) Join() (K, bool) {
+	return s.index, s.joined
+}
+
+func (s *BiBFS[K, W]) checkJoin(idx K) bool {
 	if s.rs.checkJoin(idx) {
 		s.index = idx
+		s.joined = true
 		close(s.stop)
 		return true
 	}
 	return false
 }
 
-func (s *biBFS) shouldStop() bool {
+func (s *BiBFS[K, W]) shouldStop() bool {
 	select {
 	case _, ok := <-s.stop:
 		if !ok {
@@ -99,93 +157,101 @@ func (s *biBFS) shouldStop() bool {
 	return false
 }
 
-type bfs struct {
-	parent *biBFS
-	record map[int]int
-	err    error
+type bfs[K comparable, W Weight] struct {
+	parent   *BiBFS[K, W]
+	record   map[K]Edge[K, W] // key is To
+	expanded int
+	err      error
 }
 
-func newBFS(parent *biBFS) *bfs {
-	return &bfs{
+func newBFS[K comparable, W Weight](parent *BiBFS[K, W]) *bfs[K, W] {
+	return &bfs[K, W]{
 		parent: parent,
-		record: make(map[int]int),
+		record: make(map[K]Edge[K, W]),
 	}
 }
 
-func (s *bfs) start() {
+func (s *bfs[K, W]) start() {
 	s.parent.wg.Add(1)
 	go s.run()
 }
 
-func (s *bfs) run() {
+func (s *bfs[K, W]) run() {
 	defer s.parent.wg.Done()
 
 	g := s.parent.graph
 
-	b := s.parent.source.Index
-	if b < 0 || b >= len(g.nodes) {
-		s.err = errInvalidNodeIndex
+	b := s.parent.source
+	if !g.HasNode(b) {
+		s.err = ErrInvalidNodeIndex
 		return
 	}
 
-	e := s.parent.target.Index
-	if e < 0 || e >= len(g.nodes) {
-		s.err = errInvalidNodeIndex
+	e := s.parent.target
+	if !g.HasNode(e) {
+		s.err = ErrInvalidNodeIndex
 		return
 	}
 
 	q := circularqueue.NewCircularQueue()
-	for _, tmp := range g.edges[b] {
+	for _, tmp := range g.EdgesFrom(b) {
 		q.Push(tmp)
 	}
-	s.record[b] = b
+	s.record[b] = Edge[K, W]{From: b, To: b}
+	s.expanded = 1
 
 	for !q.IsEmpty() {
+		if err := checkContext(s.parent.ctx); err != nil {
+			s.err = err
+			return
+		}
+
 		tmp, err := q.Pop()
 		if err != nil {
 			s.err = err
 			return
 		}
-		edge, ok := tmp.(graphEdge)
+		edge, ok := tmp.(Edge[K, W])
 		if !ok {
-			s.err = errEdgeTypeWrong
+			s.err = ErrEdgeTypeWrong
 			return
 		}
 
 		if s.parent.checkJoin(edge.To) {
-			s.record[edge.To] = edge.From
+			s.record[edge.To] = edge
 			return
 		}
 
 		if edge.To == e {
-			s.record[edge.To] = edge.From
+			s.record[edge.To] = edge
 			return
 		}
 
 		if _, ok := s.record[edge.To]; ok {
 			continue
 		}
-		for _, tmp := range g.edges[edge.To] {
+		for _, tmp := range g.EdgesFrom(edge.To) {
 			q.Push(tmp)
 		}
-		s.record[edge.To] = edge.From
+		s.expanded++
+		s.record[edge.To] = edge
 	}
 
-	s.err = errPathNotFound
+	s.err = ErrPathNotFound
 }
 
-func (s *bfs) result() ([]graphEdge, error) {
+func (s *bfs[K, W]) result() ([]Edge[K, W], error) {
 	if s.err != nil {
 		return nil, s.err
 	}
 
 	idx := s.parent.index
-	if idx == invalidNodeIndex {
-		idx = s.parent.target.Index
+	if !s.parent.joined {
+		idx = s.parent.target
 	}
-	edge := graphEdge{From: s.record[idx], To: idx}
-	b := s.parent.source.Index
-	var path []graphEdge
+	edge := s.record[idx]
+	b := s.parent.source
+	var path []Edge[K, W]
 
 	for {
 		path = append(path, edge)
@@ -193,125 +259,136 @@ func (s *bfs) result() ([]graphEdge, error) {
 			return reversePath(path), nil
 		}
 
-		edge = graphEdge{From: s.record[edge.From], To: edge.From}
+		edge = s.record[edge.From]
 	}
 }
 
 // The leading r represents reverse, which means search begins with target node.
-type rbfs struct {
-	parent *biBFS
+type rbfs[K comparable, W Weight] struct {
+	parent *BiBFS[K, W]
 
 	mx     *sync.Mutex
-	record map[int]int
+	record map[K]Edge[K, W] // key is To, From is one step closer to target
 
-	err error
+	expanded int
+	err      error
 }
 
-func newRBFS(parent *biBFS) *rbfs {
-	return &rbfs{
+func newRBFS[K comparable, W Weight](parent *BiBFS[K, W]) *rbfs[K, W] {
+	return &rbfs[K, W]{
 		parent: parent,
 		mx:     &sync.Mutex{},
-		record: make(map[int]int),
+		record: make(map[K]Edge[K, W]),
 	}
 }
 
-func (s *rbfs) start() {
+func (s *rbfs[K, W]) start() {
 	s.parent.wg.Add(1)
 	go s.run()
 }
 
-func (s *rbfs) run() {
+func (s *rbfs[K, W]) run() {
 	defer s.parent.wg.Done()
 
 	g := s.parent.graph
 
-	b := s.parent.target.Index
-	if b < 0 || b >= len(g.nodes) {
-		s.err = errInvalidNodeIndex
+	b := s.parent.target
+	if !g.HasNode(b) {
+		s.err = ErrInvalidNodeIndex
 		return
 	}
 
-	e := s.parent.source.Index
-	if e < 0 || e >= len(g.nodes) {
-		s.err = errInvalidNodeIndex
+	e := s.parent.source
+	if !g.HasNode(e) {
+		s.err = ErrInvalidNodeIndex
 		return
 	}
 
 	q := circularqueue.NewCircularQueue()
-	for _, tmp := range g.edges[b] {
+	for _, tmp := range g.EdgesFrom(b) {
 		q.Push(tmp)
 	}
-	s.addRecord(b, b)
+	s.addRecord(Edge[K, W]{From: b, To: b})
+	s.expanded = 1
 
 	for !q.IsEmpty() {
 		if s.parent.shouldStop() {
 			return
 		}
+		if err := checkContext(s.parent.ctx); err != nil {
+			s.err = err
+			return
+		}
 
 		tmp, err := q.Pop()
 		if err != nil {
 			s.err = err
 			return
 		}
-		edge, ok := tmp.(graphEdge)
+		edge, ok := tmp.(Edge[K, W])
 		if !ok {
-			s.err = errEdgeTypeWrong
+			s.err = ErrEdgeTypeWrong
 			return
 		}
 
 		if edge.To == e {
-			s.addRecord(edge.To, edge.From)
+			s.addRecord(edge)
 			return
 		}
 
 		if s.hasRecord(edge.To) {
 			continue
 		}
-		for _, tmp := range g.edges[edge.To] {
+		for _, tmp := range g.EdgesFrom(edge.To) {
 			q.Push(tmp)
 		}
-		s.addRecord(edge.To, edge.From)
+		s.expanded++
+		s.addRecord(edge)
 	}
 
-	s.err = errPathNotFound
+	s.err = ErrPathNotFound
 }
 
-func (s *rbfs) checkJoin(idx int) bool {
+func (s *rbfs[K, W]) checkJoin(idx K) bool {
 	return s.hasRecord(idx)
 }
 
-func (s *rbfs) hasRecord(idx int) bool {
+func (s *rbfs[K, W]) hasRecord(idx K) bool {
 	s.mx.Lock()
 	_, ok := s.record[idx]
 	s.mx.Unlock()
 	return ok
 }
 
-func (s *rbfs) addRecord(to, from int) {
+func (s *rbfs[K, W]) addRecord(edge Edge[K, W]) {
 	s.mx.Lock()
-	s.record[to] = from
+	s.record[edge.To] = edge
 	s.mx.Unlock()
 }
 
-func (s *rbfs) result() ([]graphEdge, error) {
+func (s *rbfs[K, W]) result() ([]Edge[K, W], error) {
 	if s.err != nil {
 		return nil, s.err
 	}
 
 	idx := s.parent.index
-	if idx == invalidNodeIndex {
-		idx = s.parent.source.Index
+	if !s.parent.joined {
+		idx = s.parent.source
+	}
+	e := s.parent.target
+	if idx == e {
+		return []Edge[K, W]{}, nil
 	}
-	edge := graphEdge{From: idx, To: s.record[idx]}
-	e := s.parent.target.Index
-	var path []graphEdge
+	edge := s.record[idx]
+	var path []Edge[K, W]
 
 	for {
-		path = append(path, edge)
-		if edge.To == e {
+		// turn the edge around, it is walked from source side to target side
+		path = append(path, edge.Reversed())
+		if edge.From == e {
 			return path, nil
 		}
 
-		edge = graphEdge{From: edge.To, To: s.record[edge.To]}
+		edge = s.record[edge.From]
 	}
 }
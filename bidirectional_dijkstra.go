@@ -0,0 +1,346 @@
+package graphalgo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BiDijkstra is bidirectional Dijkstra, and bidirectional A* if it has a
+// heuristic. One search goes from source on the graph, the other one goes
+// from target on the reverse graph. Every node reached by both searches
+// makes a candidate path, the cheapest one is mu. The search stops when
+// the smallest keys of the two queues sum up to mu or more, then no path
+// can be cheaper than mu.
+//
+// Bidirectional A* uses the average potentials pF(v) = (h(v, t) - h(s, v)) / 2
+// and pR(v) = -pF(v), so both searches agree on the reduced costs. The
+// keys are doubled to keep them exact with integer costs. h must be
+// consistent, which the admissible built-in heuristics are.
+type BiDijkstra[K comparable, W Weight] struct {
+	graph   *Graph[K, W]
+	reverse *Graph[K, W] // nil until the search, see SetReverse
+	shared  bool         // reverse is given by SetReverse
+	source  K
+	target  K
+	hFn     func(nd1, nd2 K) W // nil for bidirectional Dijkstra
+
+	// admissible is true if hFn is consistent, then the path found is the shortest one.
+	admissible bool
+	parallel   bool
+
+	fwd, rev *biSide[K, W]
+
+	mx   sync.Mutex // guards mu, meet and seen
+	mu   W          // cost of the cheapest path found so far
+	meet K          // the node where the cheapest path joins the two searches
+	seen bool       // true if mu is found
+
+	path  Path[K, W]
+	err   error
+	stats SearchStats
+}
+
+// biSide is one of the two searches of BiDijkstra.
+type biSide[K comparable, W Weight] struct {
+	mx    sync.Mutex
+	graph *Graph[K, W]
+	other *biSide[K, W]
+
+	dist     map[K]W          // cost from the root of the side, not final until expanded
+	key      map[K]W          // 2*dist + doubled potential, the priority
+	pred     map[K]Edge[K, W] // the edge which reaches a node, in the orientation of graph
+	settled  map[K]bool       // expanded nodes
+	pq       *IndexedPriorityQueueMin[K, W]
+	expanded int
+
+	potential func(v K) W // doubled potential
+}
+
+// NewBiDijkstra returns an instance of bidirectional Dijkstra.
+func NewBiDijkstra[K comparable, W Weight](g *Graph[K, W], s, t K) *BiDijkstra[K, W] {
+	d := &BiDijkstra[K, W]{graph: g, admissible: true}
+	d.Reset(s, t)
+	return d
+}
+
+// NewBiAstar returns an instance of bidirectional A* with heuristic h.
+// h is unknown to BiDijkstra, so the path found is not marked as optimal,
+// see SetAdmissible.
+func NewBiAstar[K comparable, W Weight](g *Graph[K, W], s, t K, h func(nd1, nd2 K) W) *BiDijkstra[K, W] {
+	d := &BiDijkstra[K, W]{graph: g, hFn: h}
+	d.Reset(s, t)
+	return d
+}
+
+// SetAdmissible tells whether the heuristic is consistent.
+// If it is, the path found is marked as optimal.
+func (d *BiDijkstra[K, W]) SetAdmissible(admissible bool) {
+	d.admissible = admissible
+}
+
+// SetReverse gives the reverse graph of the graph, see Graph.Reverse, so
+// that many searches on the same graph share it. rg must be built again
+// when the graph changes. Otherwise the search builds the reverse graph
+// and Reset drops it.
+func (d *BiDijkstra[K, W]) SetReverse(rg *Graph[K, W]) {
+	d.reverse = rg
+	d.shared = rg != nil
+}
+
+// SetParallel makes the two searches run in two goroutines, as BiBFS does.
+func (d *BiDijkstra[K, W]) SetParallel(parallel bool) {
+	d.parallel = parallel
+}
+
+// Algorithm returns AlgorithmBiDijkstra, or AlgorithmBiAstar with a heuristic.
+func (d *BiDijkstra[K, W]) Algorithm() Algorithm {
+	if d.hFn != nil {
+		return AlgorithmBiAstar
+	}
+	return AlgorithmBiDijkstra
+}
+
+// Reset forgets the last search and sets a new query.
+func (d *BiDijkstra[K, W]) Reset(s, t K) {
+	d.source = s
+	d.target = t
+	d.mu = Infinity[W]()
+	d.seen = false
+	d.path = Path[K, W]{}
+	d.err = ErrSearchIncomplete
+	d.stats = SearchStats{}
+	if !d.shared {
+		d.reverse = nil
+	}
+}
+
+// Stats returns what the last Search did.
+func (d *BiDijkstra[K, W]) Stats() SearchStats {
+	return d.stats
+}
+
+// PathToTarget returns the path found by Search.
+func (d *BiDijkstra[K, W]) PathToTarget() (Path[K, W], error) {
+	return d.path, d.err
+}
+
+// Search trys to find the shortest path from source to target.
+func (d *BiDijkstra[K, W]) Search() {
+	d.SearchContext(context.Background())
+}
+
+// SearchContext is Search which stops when ctx is done.
+func (d *BiDijkstra[K, W]) SearchContext(ctx context.Context) {
+	start := time.Now()
+	d.path, d.err = d.search(ctx)
+	d.stats = SearchStats{
+		Expanded: d.fwd.expanded + d.rev.expanded,
+		Reached:  len(d.fwd.dist) + len(d.rev.dist),
+		Elapsed:  time.Since(start),
+	}
+}
+
+func (d *BiDijkstra[K, W]) search(ctx context.Context) (Path[K, W], error) {
+	if d.reverse == nil {
+		d.reverse = d.graph.Reverse()
+	}
+	s, t := d.source, d.target
+	pf := func(v K) W { return 0 }
+	pr := pf
+	if d.hFn != nil {
+		pf = func(v K) W { return d.hFn(v, t) - d.hFn(s, v) }
+		pr = func(v K) W { return d.hFn(s, v) - d.hFn(v, t) }
+	}
+	d.fwd = newBiSide(d.graph, pf)
+	d.rev = newBiSide(d.reverse, pr)
+	d.fwd.other, d.rev.other = d.rev, d.fwd
+
+	if err := checkContext(ctx); err != nil {
+		return Path[K, W]{}, err
+	}
+	if !d.graph.HasNode(s) || !d.graph.HasNode(t) {
+		return Path[K, W]{}, ErrInvalidNodeIndex
+	}
+	d.fwd.init(s)
+	d.rev.init(t)
+	d.reach(s, d.fwd)
+
+	var err error
+	if d.parallel {
+		var wg sync.WaitGroup
+		var errs [2]error
+		stop := make(chan struct{})
+		var once sync.Once
+		for i, side := range []*biSide[K, W]{d.fwd, d.rev} {
+			wg.Add(1)
+			go func(i int, side *biSide[K, W]) {
+				defer wg.Done()
+				defer once.Do(func() { close(stop) })
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+					if errs[i] = checkContext(ctx); errs[i] != nil || d.done() {
+						return
+					}
+					if !d.expand(side) {
+						return
+					}
+				}
+			}(i, side)
+		}
+		wg.Wait()
+		err = errs[0]
+		if err == nil {
+			err = errs[1]
+		}
+	} else {
+		for {
+			if err = checkContext(ctx); err != nil || d.done() {
+				break
+			}
+			side := d.fwd
+			if d.rev.pq.Size() < d.fwd.pq.Size() {
+				side = d.rev
+			}
+			if !d.expand(side) {
+				break
+			}
+		}
+	}
+	if err != nil {
+		return Path[K, W]{}, err
+	}
+	if !d.seen {
+		return Path[K, W]{}, ErrPathNotFound
+	}
+	return d.result(), nil
+}
+
+// done returns true if no path can be cheaper than mu.
+func (d *BiDijkstra[K, W]) done() bool {
+	topF, okF := d.fwd.top()
+	topR, okR := d.rev.top()
+	if !okF || !okR {
+		return true // one side has walked all it can reach
+	}
+	d.mx.Lock()
+	defer d.mx.Unlock()
+	return d.seen && AddWeight(topF, topR) >= AddWeight(d.mu, d.mu)
+}
+
+// expand expands the top node of side. It returns false if side is empty.
+func (d *BiDijkstra[K, W]) expand(side *biSide[K, W]) bool {
+	side.mx.Lock()
+	u, err := side.pq.Pop()
+	if err != nil {
+		side.mx.Unlock()
+		return false
+	}
+	side.settled[u] = true
+	side.expanded++
+
+	var reached []K
+	for _, e := range side.graph.EdgesFrom(u) {
+		v := e.To
+		if side.settled[v] {
+			continue
+		}
+		nd := AddWeight(side.dist[u], e.Cost)
+		old, ok := side.dist[v]
+		if ok && nd >= old {
+			continue
+		}
+		side.dist[v] = nd
+		side.key[v] = AddWeight(AddWeight(nd, nd), side.potential(v))
+		side.pred[v] = e
+		if ok {
+			side.pq.ChangePriority(v)
+		} else {
+			side.pq.Insert(v)
+		}
+		reached = append(reached, v)
+	}
+	side.mx.Unlock()
+
+	for _, v := range reached {
+		d.reach(v, side)
+	}
+	return true
+}
+
+// reach updates mu if node v, reached by side, is reached by the other side too.
+func (d *BiDijkstra[K, W]) reach(v K, side *biSide[K, W]) {
+	side.mx.Lock()
+	dv := side.dist[v]
+	side.mx.Unlock()
+	other := side.other
+	other.mx.Lock()
+	ov, ok := other.dist[v]
+	other.mx.Unlock()
+	if !ok {
+		return
+	}
+
+	d.mx.Lock()
+	if c := AddWeight(dv, ov); !d.seen || c < d.mu {
+		d.mu = c
+		d.meet = v
+		d.seen = true
+	}
+	d.mx.Unlock()
+}
+
+// result returns the path through d.meet.
+func (d *BiDijkstra[K, W]) result() Path[K, W] {
+	s, t := d.source, d.target
+
+	var path []Edge[K, W]
+	for v := d.meet; v != s; {
+		e := d.fwd.pred[v]
+		path = append(path, e)
+		v = e.From
+	}
+	reversePath(path)
+	for v := d.meet; v != t; {
+		e := d.rev.pred[v]
+		path = append(path, e.Reversed()) // edges of the reverse graph are turned back
+		v = e.From
+	}
+
+	return NewPath(s, path, d.Algorithm(), d.admissible)
+}
+
+func newBiSide[K comparable, W Weight](g *Graph[K, W], potential func(v K) W) *biSide[K, W] {
+	s := &biSide[K, W]{
+		graph:     g,
+		dist:      make(map[K]W),
+		key:       make(map[K]W),
+		pred:      make(map[K]Edge[K, W]),
+		settled:   make(map[K]bool),
+		potential: potential,
+	}
+	s.pq = NewIndexedPriorityQueueMin(s.key)
+	return s
+}
+
+func (s *biSide[K, W]) init(root K) {
+	s.dist[root] = 0
+	s.key[root] = s.potential(root)
+	s.pq.Insert(root)
+}
+
+// top returns the smallest key in the queue. The bool is false if it is empty.
+func (s *biSide[K, W]) top() (W, bool) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	v, err := s.pq.Top()
+	if err != nil {
+		var zero W
+		return zero, false
+	}
+	return s.key[v], true
+}
@@ -0,0 +1,109 @@
+package graphalgo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestBiDijkstra(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for round := 0; round < 20; round++ {
+		n := 50
+		g := newTestRandomGraph(rnd, n, n*3, 20, 1)
+		rg := g.Reverse()
+
+		for q := 0; q < 20; q++ {
+			s, e := rnd.Intn(n), rnd.Intn(n)
+			dj := NewDijkstra(g, s, e)
+			dj.Search()
+			want, wantErr := dj.PathToTarget()
+
+			for _, parallel := range []bool{false, true} {
+				d := NewBiDijkstra(g, s, e)
+				d.SetReverse(rg)
+				d.SetParallel(parallel)
+				d.Search()
+				p, err := d.PathToTarget()
+				if err != wantErr {
+					t.Fatalf("parallel %v %d->%d got %v, want %v", parallel, s, e, err, wantErr)
+				}
+				if err != nil {
+					continue
+				}
+				if p.Cost != want.Cost || !p.Optimal || p.Algorithm != AlgorithmBiDijkstra {
+					t.Errorf("parallel %v %d->%d got %v, want cost %v", parallel, s, e, p, want.Cost)
+				}
+				if src, _ := p.Source(); src != s {
+					t.Errorf("parallel %v %d->%d starts at %d", parallel, s, e, src)
+				}
+				if tgt, _ := p.Target(); tgt != e {
+					t.Errorf("parallel %v %d->%d ends at %d", parallel, s, e, tgt)
+				}
+			}
+		}
+	}
+}
+
+func TestBiAstar(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	for round := 0; round < 10; round++ {
+		gr := newTestRandomGrid(rnd, 32, 24, 0.25)
+		g := gr.Graph(MovingAIOptions)
+		rg := g.Reverse()
+		h := Octile(g)
+
+		nodes := g.Nodes()
+		for q := 0; q < 20; q++ {
+			s, e := nodes[rnd.Intn(len(nodes))].ID, nodes[rnd.Intn(len(nodes))].ID
+			a := NewAstarWithH(g, s, e, h)
+			a.Search()
+			want, wantErr := a.PathToTarget()
+
+			for _, parallel := range []bool{false, true} {
+				d := NewBiAstar(g, s, e, h)
+				d.SetAdmissible(true)
+				d.SetReverse(rg)
+				d.SetParallel(parallel)
+				d.Search()
+				p, err := d.PathToTarget()
+				if err != wantErr {
+					t.Fatalf("parallel %v %d->%d got %v, want %v", parallel, s, e, err, wantErr)
+				}
+				if err != nil {
+					continue
+				}
+				if math.Abs(p.Cost-want.Cost) > 1e-9 || p.Algorithm != AlgorithmBiAstar {
+					t.Errorf("parallel %v %d->%d got %v, want cost %v", parallel, s, e, p, want.Cost)
+				}
+			}
+		}
+	}
+}
+
+func TestBiDijkstraEdgeCases(t *testing.T) {
+	d := NewBiDijkstra(newTestLineGraph(), 0, 0)
+	checkEdgeCases(t, func(s, e int) (Path[int, float64], error) {
+		d.Reset(s, e)
+		d.Search()
+		return d.PathToTarget()
+	})
+}
+
+func TestBiDijkstraGraphChanged(t *testing.T) {
+	g := newTestLineGraph()
+	d := NewBiDijkstra(g, 2, 0)
+	d.Search()
+	if _, err := d.PathToTarget(); err != ErrPathNotFound {
+		t.Fatalf("2->0 got %v, want %v", err, ErrPathNotFound)
+	}
+
+	for _, e := range [][2]int{{2, 3}, {2, 4}, {3, 0}, {4, 0}} {
+		g.AddEdge(NewEdge(e[0], e[1], 1.0))
+	}
+	d.Reset(2, 0)
+	d.Search()
+	if p, err := d.PathToTarget(); err != nil || p.Hops() != 2 {
+		t.Errorf("2->0 after AddEdge got %v %v, want two hops", p, err)
+	}
+}
@@ -0,0 +1,340 @@
+package graphalgo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"sort"
+	"unsafe"
+)
+
+// ErrChecksum tells us the content of a binary graph file is corrupted.
+var ErrChecksum = errors.New("checksum mismatch")
+
+// BinaryVersion is the version of the binary graph format.
+const BinaryVersion = 1
+
+// A binary graph file is little-endian and looks like
+//
+//	magic     [8]byte  "GRAPHCSR"
+//	version   uint32
+//	flags     uint32   binaryPositions, binaryDenseIDs
+//	costKind  uint32   reflect.Kind of W
+//	costSize  uint32   size of W in bytes
+//	nodes     uint64   n
+//	edges     uint64   m
+//	ids       [n]int64   node ID of each dense index
+//	order     [n]uint32  dense indices sorted by node ID
+//	offsets   [n+1]uint64  edges of node i are offsets[i] to offsets[i+1]
+//	costs     [m]W
+//	targets   [m]uint32  dense index of the To node of each edge
+//	positions [n][3]float64  only with binaryPositions
+//	checksum  uint32   CRC-32C of everything above
+//
+// Every section starts at a multiple of 8 bytes, so the file can be
+// mapped and read in place.
+
+const binaryMagic = "GRAPHCSR"
+
+const binaryHeaderSize = 40
+
+const (
+	binaryPositions = 1 << iota // the file has the positions section
+	binaryDenseIDs              // node ID i has dense index i
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// hostLittleEndian is true if the host stores numbers as the file does,
+// which binary graphs need to be written and read in place.
+var hostLittleEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
+
+var errBigEndian = fmt.Errorf("%w: binary graphs need a little-endian host", ErrMapFormat)
+
+// StoreBinary writes g to the binary graph file filename, see WriteBinary.
+func StoreBinary[W Weight](filename string, g *Graph[int, W]) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	err = WriteBinary(f, g)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// WriteBinary writes the nodes, edges, costs and positions of g to w in
+// the binary graph format. Labels and attributes are not written.
+func WriteBinary[W Weight](w io.Writer, g *Graph[int, W]) error {
+	if !hostLittleEndian {
+		return errBigEndian
+	}
+	nodes := g.Nodes()
+	n := len(nodes)
+	if n > math.MaxUint32 {
+		return fmt.Errorf("%w: %d nodes is too many for a binary graph", ErrMapFormat, n)
+	}
+
+	var flags uint32
+	m := 0
+	dense := true
+	for i, nd := range nodes {
+		m += len(g.edgesAt(i))
+		if nd.HasPos {
+			flags |= binaryPositions
+		}
+		if nd.ID != i {
+			dense = false
+		}
+	}
+	if dense {
+		flags |= binaryDenseIDs
+	}
+
+	crc := crc32.New(castagnoli)
+	bw := bufio.NewWriter(io.MultiWriter(w, crc))
+	var err error
+	put := func(v interface{}) {
+		if err == nil {
+			err = binary.Write(bw, binary.LittleEndian, v)
+		}
+	}
+	pad := func(size int) {
+		for ; size%8 != 0; size++ {
+			bw.WriteByte(0)
+		}
+	}
+
+	bw.WriteString(binaryMagic)
+	var zero W
+	put([4]uint32{BinaryVersion, flags, uint32(reflect.TypeOf(zero).Kind()), uint32(unsafe.Sizeof(zero))})
+	put([2]uint64{uint64(n), uint64(m)})
+
+	for _, nd := range nodes {
+		put(int64(nd.ID))
+	}
+	order := make([]uint32, n)
+	for i := range order {
+		order[i] = uint32(i)
+	}
+	sort.Slice(order, func(i, j int) bool { return nodes[order[i]].ID < nodes[order[j]].ID })
+	put(order)
+	pad(4 * n)
+
+	var offset uint64
+	put(offset)
+	for i := range nodes {
+		offset += uint64(len(g.edgesAt(i)))
+		put(offset)
+	}
+	for i := range nodes {
+		for _, e := range g.edgesAt(i) {
+			bw.Write(unsafe.Slice((*byte)(unsafe.Pointer(&e.Cost)), unsafe.Sizeof(e.Cost)))
+		}
+	}
+	pad(m * int(unsafe.Sizeof(zero)))
+	for i := range nodes {
+		for _, e := range g.edgesAt(i) {
+			to, _ := g.Index(e.To)
+			put(uint32(to))
+		}
+	}
+	pad(4 * m)
+	if flags&binaryPositions != 0 {
+		for _, nd := range nodes {
+			put([3]float64{nd.Pos.X, nd.Pos.Y, nd.Pos.Z})
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, crc.Sum32())
+}
+
+// BinaryGraph is a binary graph file opened by OpenBinary.
+type BinaryGraph[W Weight] struct {
+	data  []byte
+	unmap func() error
+	flags uint32
+
+	ids       []int64
+	order     []uint32
+	offsets   []uint64
+	costs     []W
+	targets   []uint32
+	positions [][3]float64
+
+	graph *Graph[int, W]
+}
+
+// OpenBinary maps the binary graph file filename into memory. The graph
+// reads nodes and edges from the mapped file as searches walk them, so
+// opening takes no time however large the file is. The checksum is not
+// checked, call Verify for it. W must be the cost type the file was
+// written with.
+func OpenBinary[W Weight](filename string) (*BinaryGraph[W], error) {
+	data, unmap, err := mmapFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &BinaryGraph[W]{data: data, unmap: unmap}
+	if err := b.parse(); err != nil {
+		unmap()
+		return nil, err
+	}
+	b.graph = &Graph[int, W]{src: b}
+	return b, nil
+}
+
+func (b *BinaryGraph[W]) parse() error {
+	if !hostLittleEndian {
+		return errBigEndian
+	}
+
+	data := b.data
+	if len(data) < binaryHeaderSize+4 || string(data[:8]) != binaryMagic {
+		return fmt.Errorf("%w: not a binary graph", ErrMapFormat)
+	}
+	le := binary.LittleEndian
+	if v := le.Uint32(data[8:]); v != BinaryVersion {
+		return fmt.Errorf("%w: binary graph version %d", ErrMapVersion, v)
+	}
+	b.flags = le.Uint32(data[12:])
+	if b.flags&^(binaryPositions|binaryDenseIDs) != 0 {
+		return fmt.Errorf("%w: binary graph flags %#x", ErrMapFormat, b.flags)
+	}
+	var zero W
+	if reflect.Kind(le.Uint32(data[16:])) != reflect.TypeOf(zero).Kind() || uintptr(le.Uint32(data[20:])) != unsafe.Sizeof(zero) {
+		return fmt.Errorf("%w: binary graph costs are %v of %d bytes, want %T",
+			ErrMapFormat, reflect.Kind(le.Uint32(data[16:])), le.Uint32(data[20:]), zero)
+	}
+	n, m := le.Uint64(data[24:]), le.Uint64(data[32:])
+	if n > math.MaxUint32 || m > uint64(len(data)) {
+		return fmt.Errorf("%w: binary graph size", ErrMapFormat)
+	}
+
+	off := binaryHeaderSize
+	section := func(size int) unsafe.Pointer {
+		p := unsafe.Pointer(&data[0])
+		p = unsafe.Add(p, off)
+		off += (size + 7) &^ 7
+		return p
+	}
+	size := binaryHeaderSize + 8*int(n) + (4*int(n)+7)&^7 + 8*(int(n)+1) +
+		(int(m)*int(unsafe.Sizeof(zero))+7)&^7 + (4*int(m)+7)&^7 + 4
+	if b.flags&binaryPositions != 0 {
+		size += 24 * int(n)
+	}
+	if len(data) != size {
+		return fmt.Errorf("%w: binary graph is %d bytes, want %d", ErrMapFormat, len(data), size)
+	}
+
+	b.ids = unsafe.Slice((*int64)(section(8*int(n))), n)
+	b.order = unsafe.Slice((*uint32)(section(4*int(n))), n)
+	b.offsets = unsafe.Slice((*uint64)(section(8*(int(n)+1))), n+1)
+	b.costs = unsafe.Slice((*W)(section(int(m)*int(unsafe.Sizeof(zero)))), m)
+	b.targets = unsafe.Slice((*uint32)(section(4*int(m))), m)
+	if b.flags&binaryPositions != 0 {
+		b.positions = unsafe.Slice((*[3]float64)(section(24*int(n))), n)
+	}
+	if b.offsets[0] != 0 || b.offsets[n] != m {
+		return fmt.Errorf("%w: binary graph offsets", ErrMapFormat)
+	}
+	for i := uint64(0); i < n; i++ {
+		if b.offsets[i] > b.offsets[i+1] {
+			return fmt.Errorf("%w: binary graph offsets decrease at node %d", ErrMapFormat, i)
+		}
+		if uint64(b.order[i]) >= n {
+			return fmt.Errorf("%w: binary graph order", ErrMapFormat)
+		}
+		// index looks the IDs up by binary search over order
+		if i > 0 && b.ids[b.order[i-1]] >= b.ids[b.order[i]] {
+			return fmt.Errorf("%w: binary graph order is not sorted by unique IDs", ErrMapFormat)
+		}
+		if b.flags&binaryDenseIDs != 0 && b.ids[i] != int64(i) {
+			return fmt.Errorf("%w: binary graph node %d has ID %d, not dense", ErrMapFormat, i, b.ids[i])
+		}
+	}
+	for j, to := range b.targets {
+		if uint64(to) >= n {
+			return fmt.Errorf("%w: binary graph edge %d goes to node %d of %d", ErrMapFormat, j, to, n)
+		}
+	}
+	return nil
+}
+
+// Graph returns the graph in the file. It must not be used after Close,
+// unless something has copied it into memory, such as AddNode.
+func (b *BinaryGraph[W]) Graph() *Graph[int, W] {
+	return b.graph
+}
+
+// Verify reads the whole file and returns ErrChecksum if it is corrupted.
+func (b *BinaryGraph[W]) Verify() error {
+	body := b.data[:len(b.data)-4]
+	if crc32.Checksum(body, castagnoli) != binary.LittleEndian.Uint32(b.data[len(body):]) {
+		return ErrChecksum
+	}
+	return nil
+}
+
+// Close unmaps the file.
+func (b *BinaryGraph[W]) Close() error {
+	if b.unmap == nil {
+		return nil
+	}
+	err := b.unmap()
+	b.unmap = nil
+	return err
+}
+
+func (b *BinaryGraph[W]) numNodes() int {
+	return len(b.ids)
+}
+
+func (b *BinaryGraph[W]) index(id int) (int, bool) {
+	if b.flags&binaryDenseIDs != 0 {
+		return id, id >= 0 && id < len(b.ids)
+	}
+	i := sort.Search(len(b.order), func(i int) bool { return b.ids[b.order[i]] >= int64(id) })
+	if i < len(b.order) && b.ids[b.order[i]] == int64(id) {
+		return int(b.order[i]), true
+	}
+	return InvalidNodeIndex, false
+}
+
+func (b *BinaryGraph[W]) node(idx int) Node[int] {
+	n := Node[int]{ID: int(b.ids[idx]), Index: idx}
+	if b.positions != nil {
+		p := b.positions[idx]
+		n.Pos = Position{X: p[0], Y: p[1], Z: p[2]}
+		n.HasPos = true
+	}
+	return n
+}
+
+func (b *BinaryGraph[W]) edgesFrom(idx int) Edges[int, W] {
+	from := int(b.ids[idx])
+	begin, end := b.offsets[idx], b.offsets[idx+1]
+	edges := make(Edges[int, W], 0, end-begin)
+	for j := begin; j < end; j++ {
+		edges = append(edges, NewEdge(from, int(b.ids[b.targets[j]]), b.costs[j]))
+	}
+	return edges
+}
@@ -0,0 +1,184 @@
+package graphalgo
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBinaryGraph(t *testing.T) {
+	g := newTestGraph()
+	g.SetPosition(3, Position{X: 1, Y: 2, Z: 3})
+	filename := filepath.Join(t.TempDir(), "a.csr")
+	if err := StoreBinary(filename, g); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteBinary(failWriter{}, g); err != errWriteFailed {
+		t.Errorf("WriteBinary to a failing writer got %v, want %v", err, errWriteFailed)
+	}
+
+	b, err := OpenBinary[float64](filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	if err := b.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	bg := b.Graph()
+	if bg.NumNodes() != g.NumNodes() {
+		t.Fatalf("NumNodes got %d, want %d", bg.NumNodes(), g.NumNodes())
+	}
+	if pos, ok := bg.Position(3); !ok || pos != (Position{X: 1, Y: 2, Z: 3}) {
+		t.Errorf("position of 3 got %v %v", pos, ok)
+	}
+	if bg.HasNode(100) {
+		t.Error("HasNode(100) got true")
+	}
+	for _, n := range g.Nodes() {
+		e, be := g.EdgesFrom(n.ID), bg.EdgesFrom(n.ID)
+		if len(e) != len(be) {
+			t.Fatalf("edges from %d got %v, want %v", n.ID, be, e)
+		}
+		for i := range e {
+			if e[i].From != be[i].From || e[i].To != be[i].To || e[i].Cost != be[i].Cost {
+				t.Errorf("edge got %v, want %v", be[i], e[i])
+			}
+		}
+	}
+
+	d := NewDijkstra(bg, 0, 2)
+	d.Search()
+	p, err := d.PathToTarget()
+	wd := NewDijkstra(g, 0, 2)
+	wd.Search()
+	want, _ := wd.PathToTarget()
+	if err != nil || !p.Equal(want) {
+		t.Errorf("path got %v %v, want %v", p, err, want)
+	}
+
+	// reading the graph keeps it mapped
+	if len(bg.Nodes()) != g.NumNodes() || bg.Reverse().NumNodes() != g.NumNodes() {
+		t.Error("Nodes or Reverse of a binary graph lost nodes")
+	}
+	d.Explored()
+	d.Tree().Nodes()
+	bg.WriteDOT(io.Discard, DOTOptions[int, float64]{})
+	WriteGML(io.Discard, bg, true)
+	WriteGraphML(io.Discard, bg, true)
+	NewMapData(bg)
+	NewMapDataV2(bg, true)
+	WriteBinary(io.Discard, bg)
+	if bg.src == nil {
+		t.Error("reading a binary graph copied it into memory")
+	}
+
+	// changing the graph copies it into memory
+	bg.AddEdge(NewEdge(2, 100, 1.0))
+	if !bg.HasNode(100) || bg.NumNodes() != g.NumNodes()+1 {
+		t.Error("AddEdge on a binary graph is lost")
+	}
+}
+
+func TestBinaryGraphSparseIDs(t *testing.T) {
+	g := NewGraph[int, int32]()
+	g.AddEdge(NewEdge[int, int32](30, 10, 5))
+	g.AddEdge(NewEdge[int, int32](-7, 30, 2))
+	filename := filepath.Join(t.TempDir(), "b.csr")
+	if err := StoreBinary(filename, g); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := OpenBinary[float64](filename); !errors.Is(err, ErrMapFormat) {
+		t.Errorf("open with other cost type got %v, want %v", err, ErrMapFormat)
+	}
+	b, err := OpenBinary[int32](filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	bg := b.Graph()
+	for _, id := range []int{30, 10, -7} {
+		idx, ok := bg.Index(id)
+		want, _ := g.Index(id)
+		if !ok || idx != want {
+			t.Errorf("index of %d got %d %v, want %d", id, idx, ok, want)
+		}
+	}
+	if e := bg.EdgesFrom(-7); len(e) != 1 || e[0].To != 30 || e[0].Cost != 2 {
+		t.Errorf("edges from -7 got %v", e)
+	}
+}
+
+func TestBinaryGraphCorrupted(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "c.csr")
+	if err := StoreBinary(filename, newTestGraph()); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filename)
+	n := int(binary.LittleEndian.Uint64(data[24:]))
+	data[binaryHeaderSize+8*n+(4*n+7)&^7+8*(n+1)] ^= 0xff // a cost
+	os.WriteFile(filename, data, 0644)
+
+	b, err := OpenBinary[float64](filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	if err := b.Verify(); err != ErrChecksum {
+		t.Errorf("Verify got %v, want %v", err, ErrChecksum)
+	}
+
+	os.WriteFile(filename, data[:len(data)-8], 0644)
+	if _, err := OpenBinary[float64](filename); !errors.Is(err, ErrMapFormat) {
+		t.Errorf("truncated file got %v, want %v", err, ErrMapFormat)
+	}
+}
+
+func TestBinaryGraphBadIndices(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "d.csr")
+	if err := StoreBinary(filename, newTestGraph()); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filename)
+	le := binary.LittleEndian
+	n, m := int(le.Uint64(data[24:])), int(le.Uint64(data[32:]))
+	order := binaryHeaderSize + 8*n
+	offsets := order + (4*n+7)&^7
+	targets := offsets + 8*(n+1) + 8*m
+
+	for _, tc := range []struct {
+		name string
+		at   int
+		put  func(b []byte)
+	}{
+		{"decreasing offset", offsets + 8, func(b []byte) { le.PutUint64(b, uint64(m)+1) }},
+		{"edge to no node", targets, func(b []byte) { le.PutUint32(b, uint32(n)) }},
+		{"unknown flag", 12, func(b []byte) { le.PutUint32(b, le.Uint32(b)|1<<7) }},
+		{"ID not dense", binaryHeaderSize, func(b []byte) { le.PutUint64(b, ^uint64(0)) }}, // -1
+		{"unsorted order", order, func(b []byte) {
+			i, j := le.Uint32(b), le.Uint32(b[4:])
+			le.PutUint32(b, j)
+			le.PutUint32(b[4:], i)
+		}},
+	} {
+		bad := append([]byte(nil), data...)
+		tc.put(bad[tc.at:])
+		os.WriteFile(filename, bad, 0644)
+		if _, err := OpenBinary[float64](filename); !errors.Is(err, ErrMapFormat) {
+			t.Errorf("%s got %v, want %v", tc.name, err, ErrMapFormat)
+		}
+	}
+}
+
+var errWriteFailed = errors.New("write failed")
+
+// failWriter is an io.Writer that always fails.
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) { return 0, errWriteFailed }
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"graph-algo"
+)
+
+var pn = fmt.Println
+var pf = fmt.Printf
+
+func loadMap(name string) (*graphalgo.MapData, error) {
+	md := &graphalgo.MapData{}
+	err := md.Load("bin/" + name)
+	if err != nil {
+		err = md.Load("./" + name)
+		if err != nil {
+			err = md.Load("../../bin/" + name)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return md, nil
+}
+
+func main() {
+	pn("directional")
+	{
+		md, err := loadMap("a.map")
+		if err != nil {
+			pf("load a.map got error %v\n", err)
+			return
+		}
+		md.Show()
+		pn()
+
+		g := md.Graph()
+		g.Show()
+
+		path, err := g.DFS(0, 1)
+		if err != nil {
+			pn(err)
+			return
+		}
+		pf("dfs %d->%d    %v\n", 0, 1, path)
+
+		path, err = g.BFS(0, 2)
+		if err != nil {
+			pn(err)
+			return
+		}
+		pf("bfs %d->%d    %v\n", 0, 2, path)
+	}
+
+	pn("non-directional Bidirectional BFS")
+	{
+		md, err := loadMap("a2.map")
+		if err != nil {
+			pf("load a2.map got error %v\n", err)
+			return
+		}
+		md.Show()
+		pn()
+
+		// a2.map is non-directional, every edge is added both ways
+		g := md.Graph()
+		g.Show()
+
+		bs := graphalgo.NewBiBFS(g, 0, 5)
+		bs.Search()
+		pn(bs.PathToTarget())
+		pn(bs.Join())
+	}
+
+	pn("directional dijkstra")
+	{
+		md, err := loadMap("a.map")
+		if err != nil {
+			pf("load a.map got error %v\n", err)
+			return
+		}
+		g := md.Graph()
+
+		d := graphalgo.NewDijkstra(g, 4, 2)
+		d.Search()
+		pn(d.PathToTarget())
+	}
+
+	pn("directional astar")
+	{
+		md, err := loadMap("a.map")
+		if err != nil {
+			pf("load a.map got error %v\n", err)
+			return
+		}
+		g := md.Graph()
+
+		d := graphalgo.NewAstar(g, 4, 2)
+		d.Search()
+		pn(d.PathToTarget())
+	}
+}
@@ -0,0 +1,48 @@
+// movingai runs the scenarios of a Moving AI benchmark and reports the
+// ones whose cost is not optimal.
+//
+//	movingai -map arena.map -scen arena.map.scen -algo astar,jps+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"graph-algo"
+	"os"
+	"strings"
+)
+
+var pn = fmt.Println
+
+func main() {
+	mapFile := flag.String("map", "", "Moving AI .map file")
+	scenFile := flag.String("scen", "", "Moving AI .scen file")
+	algos := flag.String("algo", "astar,dijkstra,jps,jps+", "comma separated algorithms")
+	flag.Parse()
+
+	gr, err := graphalgo.LoadMovingAIMap(*mapFile)
+	if err != nil {
+		pn(err)
+		os.Exit(1)
+	}
+	scens, err := graphalgo.LoadScenarios(*scenFile)
+	if err != nil {
+		pn(err)
+		os.Exit(1)
+	}
+
+	g := gr.Graph(graphalgo.MovingAIOptions)
+	failed := false
+	for _, a := range strings.Split(*algos, ",") {
+		rep, err := graphalgo.RunScenarios(gr, g, scens, graphalgo.Algorithm(a))
+		if err != nil {
+			pn(a, err)
+			os.Exit(1)
+		}
+		pn(rep)
+		failed = failed || rep.Mismatches > 0
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,385 @@
+package graphalgo
+
+// AlgorithmCH names the paths found by CH.
+const AlgorithmCH Algorithm = "ch"
+
+// chWitnessSettled is how many nodes a witness search expands at most.
+// A search which gives up early only adds a shortcut which is not needed.
+const chWitnessSettled = 500
+
+// CH is a contraction hierarchy of a static graph, for fast point-to-point
+// queries. Nodes are contracted one by one, the one with the smallest edge
+// difference (shortcuts added minus edges removed) first, plus how many
+// neighbors and how deep a level of them are contracted, which spreads
+// contraction over the graph. Contracting v
+// adds the shortcut u->w for every path u->v->w which is the only shortest
+// one, as a witness search from u which skips v tells. The rank of a node
+// is when it was contracted.
+//
+// A query is a bidirectional Dijkstra which only walks edges and shortcuts
+// to nodes of higher rank, from the source on the graph and from the
+// target on the reverse graph. Shortcuts of the path found are unpacked
+// into the edges of the graph.
+//
+// CH must be built again when the graph changes. FindPath does not change
+// it, so many queries may run concurrently.
+type CH[K comparable, W Weight] struct {
+	graph *Graph[K, W]
+	rank  []int                 // by dense index
+	up    [][]chEdge[W]         // up[v] are the edges v->w with rank[w] > rank[v]
+	down  [][]chEdge[W]         // down[v] are the edges u->v with rank[u] > rank[v], to is u
+	edge  map[[2]int]Edge[K, W] // the cheapest edge between two dense indices
+}
+
+// chEdge is an edge or a shortcut of CH, between dense indices.
+type chEdge[W Weight] struct {
+	to   int
+	cost W
+	mid  int // the contracted node the shortcut skips, -1 for an edge of the graph
+}
+
+// chBuilder keeps the graph of the nodes not contracted yet.
+type chBuilder[K comparable, W Weight] struct {
+	ch  *CH[K, W]
+	out [][]chEdge[W] // out[u] are the cheapest edges from u, one to each node
+	in  [][]chEdge[W] // in[w] are the same edges to w, to is the node they are from
+
+	deleted []int // contracted neighbors of each node
+	level   []int // 1 + the highest level of the contracted neighbors
+}
+
+// chShortcut is a shortcut u->w which contracting a node adds.
+type chShortcut[W Weight] struct {
+	from, to int
+	cost     W
+}
+
+// NewCH contracts every node of g and returns the hierarchy.
+func NewCH[K comparable, W Weight](g *Graph[K, W]) *CH[K, W] {
+	n := g.NumNodes()
+	ch := &CH[K, W]{
+		graph: g,
+		rank:  make([]int, n),
+		up:    make([][]chEdge[W], n),
+		down:  make([][]chEdge[W], n),
+		edge:  make(map[[2]int]Edge[K, W]),
+	}
+	b := &chBuilder[K, W]{
+		ch:      ch,
+		out:     make([][]chEdge[W], n),
+		in:      make([][]chEdge[W], n),
+		deleted: make([]int, n),
+		level:   make([]int, n),
+	}
+	for u := 0; u < n; u++ {
+		nd, _ := g.Node(u)
+		for _, e := range g.EdgesFrom(nd.ID) {
+			w, _ := g.Index(e.To)
+			if w != u && b.link(u, w, e.Cost, -1) {
+				ch.edge[[2]int{u, w}] = e
+			}
+		}
+	}
+
+	priority := make(map[int]int, n)
+	pq := NewIndexedPriorityQueueMinWithSize(priority, n)
+	for v := 0; v < n; v++ {
+		priority[v], _ = b.simulate(v)
+		pq.Insert(v)
+	}
+
+	for r := 0; !pq.IsEmpty(); {
+		v, _ := pq.Pop()
+		p, shortcuts := b.simulate(v)
+		if top, err := pq.Top(); err == nil && p > priority[top] {
+			priority[v] = p // lazy update, v got worse since it was queued
+			pq.Insert(v)
+			continue
+		}
+		b.contract(v, shortcuts)
+		ch.rank[v] = r
+		r++
+	}
+	return ch
+}
+
+// simulate returns the priority of contracting v now, and the shortcuts it adds.
+func (b *chBuilder[K, W]) simulate(v int) (int, []chShortcut[W]) {
+	var shortcuts []chShortcut[W]
+	targets := make(map[int]bool, len(b.out[v]))
+	for _, out := range b.out[v] {
+		targets[out.to] = true
+	}
+	for _, in := range b.in[v] {
+		u := in.to
+		var limit W
+		for _, out := range b.out[v] {
+			if out.to != u && AddWeight(in.cost, out.cost) > limit {
+				limit = AddWeight(in.cost, out.cost)
+			}
+		}
+		dist := b.witness(u, v, limit, targets)
+		for _, out := range b.out[v] {
+			if out.to == u {
+				continue
+			}
+			c := AddWeight(in.cost, out.cost)
+			if d, ok := dist[out.to]; !ok || d > c {
+				shortcuts = append(shortcuts, chShortcut[W]{from: u, to: out.to, cost: c})
+			}
+		}
+	}
+	return len(shortcuts) - len(b.in[v]) - len(b.out[v]) + b.deleted[v] + b.level[v], shortcuts
+}
+
+// witness runs Dijkstra from u on the nodes not contracted, without skip,
+// until it expands a node farther than limit, all targets, or
+// chWitnessSettled nodes. It returns the cost of a path to every node it
+// reached, which may not be the shortest one if it is not expanded.
+func (b *chBuilder[K, W]) witness(u, skip int, limit W, targets map[int]bool) map[int]W {
+	left := len(targets)
+	dist := map[int]W{u: 0}
+	pq := NewIndexedPriorityQueueMin(dist)
+	pq.Insert(u)
+	for settled := 0; !pq.IsEmpty() && settled < chWitnessSettled; settled++ {
+		x, _ := pq.Pop()
+		if dist[x] > limit {
+			break
+		}
+		if targets[x] {
+			if left--; left == 0 {
+				break
+			}
+		}
+		for _, e := range b.out[x] {
+			y := e.to
+			if y == skip {
+				continue
+			}
+			c := AddWeight(dist[x], e.cost)
+			old, ok := dist[y]
+			if ok && c >= old {
+				continue
+			}
+			dist[y] = c
+			if ok {
+				pq.ChangePriority(y)
+			} else {
+				pq.Insert(y)
+			}
+		}
+	}
+	return dist
+}
+
+// contract removes v from the graph of the builder, moves its edges into
+// the hierarchy and adds shortcuts.
+func (b *chBuilder[K, W]) contract(v int, shortcuts []chShortcut[W]) {
+	ch := b.ch
+	ch.up[v], ch.down[v] = b.out[v], b.in[v]
+	for _, e := range b.out[v] {
+		b.in[e.to] = chRemove(b.in[e.to], v)
+		b.contracted(e.to, v)
+	}
+	for _, e := range b.in[v] {
+		b.out[e.to] = chRemove(b.out[e.to], v)
+		b.contracted(e.to, v)
+	}
+	b.out[v], b.in[v] = nil, nil
+
+	for _, s := range shortcuts {
+		b.link(s.from, s.to, s.cost, v)
+	}
+}
+
+// contracted tells neighbor u that v is contracted.
+func (b *chBuilder[K, W]) contracted(u, v int) {
+	b.deleted[u]++
+	if b.level[u] < b.level[v]+1 {
+		b.level[u] = b.level[v] + 1
+	}
+}
+
+// link adds the edge u->w, or makes it cheaper, and returns true. It
+// returns false if there is one which costs no more.
+func (b *chBuilder[K, W]) link(u, w int, cost W, mid int) bool {
+	i := chFind(b.out[u], w)
+	if i >= 0 && b.out[u][i].cost <= cost {
+		return false
+	}
+	if i >= 0 {
+		b.out[u][i] = chEdge[W]{to: w, cost: cost, mid: mid}
+		b.in[w][chFind(b.in[w], u)] = chEdge[W]{to: u, cost: cost, mid: mid}
+		return true
+	}
+	b.out[u] = append(b.out[u], chEdge[W]{to: w, cost: cost, mid: mid})
+	b.in[w] = append(b.in[w], chEdge[W]{to: u, cost: cost, mid: mid})
+	return true
+}
+
+// chFind returns the index of the edge to node to in edges, or -1.
+func chFind[W Weight](edges []chEdge[W], to int) int {
+	for i, e := range edges {
+		if e.to == to {
+			return i
+		}
+	}
+	return -1
+}
+
+// chRemove removes the edge to node to from edges.
+func chRemove[W Weight](edges []chEdge[W], to int) []chEdge[W] {
+	if i := chFind(edges, to); i >= 0 {
+		edges[i] = edges[len(edges)-1]
+		edges = edges[:len(edges)-1]
+	}
+	return edges
+}
+
+// Rank returns when node id was contracted, from 0, or -1 if it is not in the graph.
+func (ch *CH[K, W]) Rank(id K) int {
+	idx, ok := ch.graph.Index(id)
+	if !ok {
+		return -1
+	}
+	return ch.rank[idx]
+}
+
+// NumShortcuts returns how many shortcuts the hierarchy has.
+func (ch *CH[K, W]) NumShortcuts() int {
+	n := 0
+	for _, edges := range ch.up {
+		for _, e := range edges {
+			if e.mid >= 0 {
+				n++
+			}
+		}
+	}
+	for _, edges := range ch.down {
+		for _, e := range edges {
+			if e.mid >= 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// chSide is one of the two searches of a CH query.
+type chSide[W Weight] struct {
+	edges [][]chEdge[W]
+	dist  map[int]W
+	pred  map[int]chPred[W]
+	pq    *IndexedPriorityQueueMin[int, W]
+}
+
+// chPred is the edge or shortcut which reaches a node, from node from.
+type chPred[W Weight] struct {
+	from int
+	e    chEdge[W]
+}
+
+func newCHSide[W Weight](edges [][]chEdge[W], root int) *chSide[W] {
+	s := &chSide[W]{
+		edges: edges,
+		dist:  map[int]W{root: 0},
+		pred:  make(map[int]chPred[W]),
+	}
+	s.pq = NewIndexedPriorityQueueMin(s.dist)
+	s.pq.Insert(root)
+	return s
+}
+
+// top returns the smallest cost in the queue. The bool is false if it is empty.
+func (s *chSide[W]) top() (W, bool) {
+	v, err := s.pq.Top()
+	if err != nil {
+		var zero W
+		return zero, false
+	}
+	return s.dist[v], true
+}
+
+// FindPath returns the shortest path from node s to node t.
+func (ch *CH[K, W]) FindPath(s, t K) (Path[K, W], error) {
+	si, ok1 := ch.graph.Index(s)
+	ti, ok2 := ch.graph.Index(t)
+	if !ok1 || !ok2 {
+		return Path[K, W]{}, ErrInvalidNodeIndex
+	}
+
+	fwd, bwd := newCHSide(ch.up, si), newCHSide(ch.down, ti)
+	mu, meet := Infinity[W](), -1
+	if si == ti {
+		mu, meet = 0, si
+	}
+	for {
+		topF, okF := fwd.top()
+		topB, okB := bwd.top()
+		okF = okF && topF < mu
+		okB = okB && topB < mu
+		if !okF && !okB {
+			break
+		}
+		side, other := fwd, bwd
+		if !okF || (okB && topB < topF) {
+			side, other = bwd, fwd
+		}
+
+		u, _ := side.pq.Pop()
+		for _, e := range side.edges[u] {
+			c := AddWeight(side.dist[u], e.cost)
+			old, ok := side.dist[e.to]
+			if ok && c >= old {
+				continue
+			}
+			side.dist[e.to] = c
+			side.pred[e.to] = chPred[W]{from: u, e: e}
+			if ok {
+				side.pq.ChangePriority(e.to)
+			} else {
+				side.pq.Insert(e.to)
+			}
+			if od, ok := other.dist[e.to]; ok && AddWeight(c, od) < mu {
+				mu, meet = AddWeight(c, od), e.to
+			}
+		}
+	}
+	if meet < 0 {
+		return Path[K, W]{}, ErrPathNotFound
+	}
+
+	var edges []Edge[K, W]
+	var chain []chPred[W]
+	for v := meet; v != si; v = fwd.pred[v].from {
+		chain = append(chain, fwd.pred[v])
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		edges = ch.unpack(edges, chain[i].from, chain[i].e)
+	}
+	for v := meet; v != ti; v = bwd.pred[v].from {
+		p := bwd.pred[v]
+		edges = ch.unpack(edges, v, chEdge[W]{to: p.from, cost: p.e.cost, mid: p.e.mid})
+	}
+	return NewPath(s, edges, AlgorithmCH, true), nil
+}
+
+// unpack appends the edges of the graph which e, from node from, stands for.
+func (ch *CH[K, W]) unpack(edges []Edge[K, W], from int, e chEdge[W]) []Edge[K, W] {
+	if e.mid < 0 {
+		return append(edges, ch.edge[[2]int{from, e.to}])
+	}
+	for _, d := range ch.down[e.mid] {
+		if d.to == from {
+			edges = ch.unpack(edges, from, chEdge[W]{to: e.mid, cost: d.cost, mid: d.mid})
+			break
+		}
+	}
+	for _, u := range ch.up[e.mid] {
+		if u.to == e.to {
+			edges = ch.unpack(edges, e.mid, u)
+			break
+		}
+	}
+	return edges
+}
@@ -0,0 +1,92 @@
+package graphalgo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestCH(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for round := 0; round < 10; round++ {
+		n := 120
+		g := newTestRandomGraph(rnd, n, n*3, 30, 3) // sparse IDs
+		ch := NewCH(g)
+
+		for q := 0; q < 40; q++ {
+			s, e := rnd.Intn(n)*3, rnd.Intn(n)*3
+			d := NewDijkstra(g, s, e)
+			d.Search()
+			want, wantErr := d.PathToTarget()
+
+			p, err := ch.FindPath(s, e)
+			if err != wantErr {
+				t.Fatalf("%d->%d got %v, want %v", s, e, err, wantErr)
+			}
+			if err != nil {
+				continue
+			}
+			if p.Cost != want.Cost || !p.Optimal || p.Algorithm != AlgorithmCH {
+				t.Errorf("%d->%d got %v, want cost %v", s, e, p, want.Cost)
+			}
+			checkCHPath(t, g, p, s, e)
+		}
+	}
+}
+
+func TestCHGrid(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	gr := newTestRandomGrid(rnd, 30, 20, 0.2)
+	g := gr.Graph(MovingAIOptions)
+	ch := NewCH(g)
+
+	nodes := g.Nodes()
+	for q := 0; q < 50; q++ {
+		s, e := nodes[rnd.Intn(len(nodes))].ID, nodes[rnd.Intn(len(nodes))].ID
+		d := NewDijkstra(g, s, e)
+		d.Search()
+		want, wantErr := d.PathToTarget()
+
+		p, err := ch.FindPath(s, e)
+		if err != wantErr {
+			t.Fatalf("%d->%d got %v, want %v", s, e, err, wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if math.Abs(p.Cost-want.Cost) > 1e-9 {
+			t.Errorf("%d->%d got cost %v, want %v", s, e, p.Cost, want.Cost)
+		}
+		checkCHPath(t, g, p, s, e)
+	}
+}
+
+func TestCHEdgeCases(t *testing.T) {
+	ch := NewCH(newTestLineGraph())
+	checkEdgeCases(t, ch.FindPath)
+	if ch.Rank(9) != -1 {
+		t.Errorf("rank of a missing node got %d, want -1", ch.Rank(9))
+	}
+}
+
+// checkCHPath checks that p walks edges of g from s to e.
+func checkCHPath[W Weight](t *testing.T, g *Graph[int, W], p Path[int, W], s, e int) {
+	t.Helper()
+	at := s
+	for _, edge := range p.Edges {
+		if edge.From != at {
+			t.Fatalf("%d->%d path %v is broken at %d", s, e, p, at)
+		}
+		found := false
+		for _, ge := range g.EdgesFrom(at) {
+			found = found || (ge.To == edge.To && ge.Cost == edge.Cost)
+		}
+		if !found {
+			t.Fatalf("%d->%d path %v has %v which is not in the graph", s, e, p, edge)
+		}
+		at = edge.To
+	}
+	if at != e {
+		t.Fatalf("%d->%d path %v ends at %d", s, e, p, at)
+	}
+}
@@ -0,0 +1,120 @@
+package graphalgo
+
+import (
+	"context"
+	"github.com/ZhangGuangxu/stack"
+	"time"
+)
+
+// DFS is deep first search. It uses a stack as its core data architecture.
+type DFS[K comparable, W Weight] struct {
+	graph  *Graph[K, W]
+	source K
+	target K
+
+	path  Path[K, W]
+	err   error
+	stats SearchStats
+}
+
+// NewDFS returns an instance of DFS.
+func NewDFS[K comparable, W Weight](g *Graph[K, W], s, t K) *DFS[K, W] {
+	d := &DFS[K, W]{graph: g}
+	d.Reset(s, t)
+	return d
+}
+
+// Algorithm returns AlgorithmDFS.
+func (d *DFS[K, W]) Algorithm() Algorithm {
+	return AlgorithmDFS
+}
+
+// Reset forgets the last search and sets a new query.
+func (d *DFS[K, W]) Reset(s, t K) {
+	d.source = s
+	d.target = t
+	d.path = Path[K, W]{}
+	d.err = ErrSearchIncomplete
+	d.stats = SearchStats{}
+}
+
+// Search trys to find a path from source to target.
+func (d *DFS[K, W]) Search() {
+	d.SearchContext(context.Background())
+}
+
+// SearchContext is Search which stops when ctx is done.
+func (d *DFS[K, W]) SearchContext(ctx context.Context) {
+	start := time.Now()
+	d.path, d.err = d.search(ctx)
+	d.stats.Elapsed = time.Since(start)
+}
+
+// PathToTarget returns the path found by Search.
+func (d *DFS[K, W]) PathToTarget() (Path[K, W], error) {
+	return d.path, d.err
+}
+
+// Stats returns what the last Search did.
+func (d *DFS[K, W]) Stats() SearchStats {
+	return d.stats
+}
+
+func (d *DFS[K, W]) search(ctx context.Context) (Path[K, W], error) {
+	b := d.source
+	e := d.target
+	if !d.graph.HasNode(b) || !d.graph.HasNode(e) {
+		return Path[K, W]{}, ErrInvalidNodeIndex
+	}
+
+	if b == e {
+		return NewPath[K, W](b, nil, AlgorithmDFS, false), nil
+	}
+
+	s := stack.NewStack()
+	for _, tmp := range d.graph.EdgesFrom(b) {
+		s.Push(tmp)
+	}
+	record := make(map[K]Edge[K, W])       // 用于记录曾经加入过栈的边，key是To
+	record[b] = Edge[K, W]{From: b, To: b} // 起始点比较特殊
+	d.stats.Expanded = 1
+	defer func() { d.stats.Reached = len(record) }()
+
+	for !s.IsEmpty() {
+		if err := checkContext(ctx); err != nil {
+			return Path[K, W]{}, err
+		}
+
+		tmpEdge, err := s.Pop()
+		if err != nil {
+			return Path[K, W]{}, err
+		}
+		edge, ok := tmpEdge.(Edge[K, W])
+		if !ok {
+			return Path[K, W]{}, ErrEdgeTypeWrong
+		}
+
+		if edge.To == e {
+			var path []Edge[K, W]
+			for {
+				path = append(path, edge)
+				if edge.From == b {
+					return NewPath(b, reversePath(path), AlgorithmDFS, false), nil
+				}
+
+				edge = record[edge.From]
+			}
+		}
+
+		if _, ok := record[edge.To]; ok {
+			continue
+		}
+		for _, tmp := range d.graph.EdgesFrom(edge.To) {
+			s.Push(tmp)
+		}
+		d.stats.Expanded++
+		record[edge.To] = edge
+	}
+
+	return Path[K, W]{}, ErrPathNotFound
+}
@@ -1,90 +1,239 @@
-package main
+package graphalgo
+
+import (
+	"context"
+	"time"
+)
 
 // Dijkstra algorithm
-type Dijkstra struct {
-	graph  *graph
-	source int
-	target int
+type Dijkstra[K comparable, W Weight] struct {
+	graph  *Graph[K, W]
+	source K
+	target K
+
+	frontier map[K]Edge[K, W] // search frontier
+	cost     map[K]W          // cost to some node
+	spt      map[K]Edge[K, W] // shortest path tree
+
+	costFn func(e Edge[K, W]) (W, bool) // nil means e.Cost
+
+	tree   bool // true to build the shortest path tree instead of stopping at target
+	radius W    // the tree has the nodes no farther than radius
 
-	frontier map[int]graphEdge // search frontier
-	cost     map[int]float32   // cost to some node
-	spt      map[int]graphEdge // shortest path tree
+	pq    *IndexedPriorityQueueMin[K, W] // kept between steps, nil before the first step
+	state StepState
 
-	err error
+	err   error
+	stats SearchStats
 }
 
 // NewDijkstra returns a instance of Dijkstra.
-func NewDijkstra(g *graph, s, t int) *Dijkstra {
-	return &Dijkstra{
-		graph:    g,
-		source:   s,
-		target:   t,
-		frontier: make(map[int]graphEdge),
-		cost:     make(map[int]float32),
-		spt:      make(map[int]graphEdge),
-	}
+func NewDijkstra[K comparable, W Weight](g *Graph[K, W], s, t K) *Dijkstra[K, W] {
+	d := &Dijkstra[K, W]{graph: g}
+	d.Reset(s, t)
+	return d
+}
+
+// NewDijkstraTree returns an instance of Dijkstra which builds the
+// shortest path tree of s. Search does not stop at a target, it expands
+// every node which costs at most radius from s, Infinity[W]() means
+// every node s can reach. Tree returns what it has found.
+func NewDijkstraTree[K comparable, W Weight](g *Graph[K, W], s K, radius W) *Dijkstra[K, W] {
+	d := &Dijkstra[K, W]{graph: g, tree: true, radius: radius}
+	d.Reset(s, s)
+	return d
+}
+
+// SetCostFunc makes the search walk edge e with cost fn(e) instead of
+// e.Cost. If fn returns false, e can not be walked. fn may read the
+// attributes of e, and of its nodes by Graph.NodeByID.
+func (d *Dijkstra[K, W]) SetCostFunc(fn func(e Edge[K, W]) (W, bool)) {
+	d.costFn = fn
+}
+
+// Algorithm returns AlgorithmDijkstra.
+func (d *Dijkstra[K, W]) Algorithm() Algorithm {
+	return AlgorithmDijkstra
+}
+
+// Reset forgets the last search and sets a new query.
+func (d *Dijkstra[K, W]) Reset(s, t K) {
+	d.source = s
+	d.target = t
+	d.frontier = make(map[K]Edge[K, W])
+	d.cost = make(map[K]W)
+	d.spt = make(map[K]Edge[K, W])
+	d.pq = nil
+	d.state = StepSearching
+	d.err = nil
+	d.stats = SearchStats{}
+}
+
+// Stats returns what the last Search did.
+func (d *Dijkstra[K, W]) Stats() SearchStats {
+	return d.stats
 }
 
 // Search trys to find the shortest path from source to target.
-// source is a node index, same as target.
-func (d *Dijkstra) Search() {
-	d.frontier[d.source] = graphEdge{From: d.source, To: d.source} // source node is special
-	d.cost[d.source] = 0
-	pq := NewIndexedPriorityQueueMin(d.cost)
-	pq.Insert(d.source)
-
-	for !pq.IsEmpty() {
-		idx, err := pq.Pop()
-		if err != nil {
+// source is a node ID, same as target.
+func (d *Dijkstra[K, W]) Search() {
+	d.SearchContext(context.Background())
+}
+
+// SearchContext is Search which stops when ctx is done.
+func (d *Dijkstra[K, W]) SearchContext(ctx context.Context) {
+	for {
+		if err := checkContext(ctx); err != nil {
 			d.err = err
+			d.state = StepFailed
 			return
 		}
+		if d.Step(1) != StepSearching {
+			return
+		}
+	}
+}
+
+// Step expands at most maxExpansions nodes, then returns the state of the search.
+// maxExpansions <= 0 means no limit. The search goes on where it stopped
+// when Step is called again.
+func (d *Dijkstra[K, W]) Step(maxExpansions int) StepState {
+	if d.state != StepSearching {
+		return d.state
+	}
+
+	start := time.Now()
+	defer func() {
+		d.stats.Reached = len(d.frontier)
+		d.stats.Elapsed += time.Since(start)
+	}()
+
+	if d.pq == nil {
+		if !d.graph.HasNode(d.source) || !d.graph.HasNode(d.target) {
+			d.err = ErrInvalidNodeIndex
+			d.state = StepFailed
+			return d.state
+		}
+
+		d.frontier[d.source] = Edge[K, W]{From: d.source, To: d.source} // source node is special
+		d.cost[d.source] = 0
+		d.pq = NewIndexedPriorityQueueMin(d.cost)
+		d.pq.Insert(d.source)
+	}
+
+	for n := 0; maxExpansions <= 0 || n < maxExpansions; n++ {
+		if d.pq.IsEmpty() {
+			d.state = StepFailed
+			if d.tree {
+				d.state = StepFound
+			}
+			return d.state
+		}
+
+		idx, err := d.pq.Pop()
+		if err != nil {
+			d.err = err
+			d.state = StepFailed
+			return d.state
+		}
+
+		if d.tree && d.cost[idx] > d.radius {
+			d.state = StepFound // so are the nodes left in the queue
+			return d.state
+		}
 
 		edge := d.frontier[idx]
 		d.spt[idx] = edge
+		d.stats.Expanded++
 		i := edge.To
 
-		if i == d.target {
-			return
+		if i == d.target && !d.tree {
+			d.state = StepFound
+			return d.state
 		}
 
-		for _, e := range d.graph.edges[i] {
-			newCost := d.cost[i] + e.Cost
+		for _, e := range d.graph.EdgesFrom(i) {
+			c, ok := d.edgeCost(e)
+			if !ok {
+				continue
+			}
+			newCost := AddWeight(d.cost[i], c)
 			t := e.To
 			if _, ok := d.frontier[t]; !ok {
 				d.frontier[t] = e
 				d.cost[t] = newCost
-				pq.Insert(t)
+				d.pq.Insert(t)
 			} else if newCost < d.cost[t] {
 				if _, ok := d.spt[t]; !ok {
 					d.frontier[t] = e
 					d.cost[t] = newCost
-					pq.ChangePriority(t)
+					d.pq.ChangePriority(t)
 				}
 			}
 		}
 	}
+
+	return d.state
 }
 
-// PathToTarget returns shortest path from source to target.
-func (d *Dijkstra) PathToTarget() ([]graphEdge, error) {
-	if d.err != nil {
-		return []graphEdge{}, d.err
-	}
+// Explored returns the nodes which have been expanded, in the order of
+// their dense indices.
+func (d *Dijkstra[K, W]) Explored() []K {
+	return exploredNodes(d.graph, d.spt)
+}
 
-	var path []graphEdge
-	idx := d.target
-	for {
-		if idx == d.source {
-			break
+// Frontier returns the nodes which have been reached but not expanded yet,
+// in the order of their dense indices.
+func (d *Dijkstra[K, W]) Frontier() []K {
+	return frontierNodes(d.graph, d.frontier, d.spt)
+}
+
+// exploredNodes returns the nodes of g which are in spt, in the order of
+// their dense indices.
+func exploredNodes[K comparable, W Weight](g *Graph[K, W], spt map[K]Edge[K, W]) []K {
+	var nodes []K
+	g.walkNodes(func(_ int, n Node[K]) {
+		if _, ok := spt[n.ID]; ok {
+			nodes = append(nodes, n.ID)
 		}
-		e, ok := d.spt[idx]
-		if !ok {
-			break
+	})
+	return nodes
+}
+
+// frontierNodes returns the nodes of g which are in frontier but not in
+// spt, in the order of their dense indices.
+func frontierNodes[K comparable, W Weight](g *Graph[K, W], frontier, spt map[K]Edge[K, W]) []K {
+	var nodes []K
+	g.walkNodes(func(_ int, n Node[K]) {
+		_, reached := frontier[n.ID]
+		_, expanded := spt[n.ID]
+		if reached && !expanded {
+			nodes = append(nodes, n.ID)
 		}
-		path = append(path, e)
-		idx = e.From
+	})
+	return nodes
+}
+
+// PathToTarget returns shortest path from source to target.
+func (d *Dijkstra[K, W]) PathToTarget() (Path[K, W], error) {
+	if d.err != nil {
+		return Path[K, W]{}, d.err
 	}
+	if d.state == StepSearching {
+		return Path[K, W]{}, ErrSearchIncomplete
+	}
+	return d.Tree().PathTo(d.target)
+}
 
-	return reversePath(path), nil
+// Tree returns the shortest path tree of the nodes expanded so far.
+// It is the whole tree once a search by NewDijkstraTree is done.
+func (d *Dijkstra[K, W]) Tree() *ShortestPathTree[K, W] {
+	return &ShortestPathTree[K, W]{graph: d.graph, source: d.source, cost: d.cost, spt: d.spt}
+}
+
+func (d *Dijkstra[K, W]) edgeCost(e Edge[K, W]) (W, bool) {
+	if d.costFn == nil {
+		return e.Cost, true
+	}
+	return d.costFn(e)
 }
@@ -0,0 +1,126 @@
+package graphalgo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DIMACSCoordScale is what the coordinates in DIMACS .co files are
+// multiplied by. They are longitude and latitude in millionths of degrees.
+const DIMACSCoordScale = 1e6
+
+// ReadDIMACS reads a graph in the format of the 9th DIMACS Implementation
+// Challenge. gr is the .gr file, with the "p sp n m" line and one
+// "a u v w" line per arc. Nodes are 1 to n, and node i has dense index i-1.
+// co is the .co file, with "v id x y" lines, it may be nil. Coordinates
+// are divided by DIMACSCoordScale, so X is longitude and Y is latitude in
+// degrees, ready for Haversine. Both files are read line by line.
+func ReadDIMACS(gr, co io.Reader) (*Graph[int, int64], error) {
+	g := NewGraph[int, int64]()
+
+	err := scanDIMACS(gr, "gr", func(line int, f []string) error {
+		switch f[0] {
+		case "p":
+			if len(f) != 4 || f[1] != "sp" {
+				return fmt.Errorf("bad problem line")
+			}
+			n, err := strconv.Atoi(f[2])
+			if err != nil || g.NumNodes() > 0 {
+				return fmt.Errorf("bad problem line")
+			}
+			for i := 1; i <= n; i++ {
+				g.AddNode(NewNode(i))
+			}
+		case "a":
+			if len(f) != 4 {
+				return fmt.Errorf("bad arc line")
+			}
+			u, err1 := strconv.Atoi(f[1])
+			v, err2 := strconv.Atoi(f[2])
+			w, err3 := strconv.ParseInt(f[3], 10, 64)
+			if err1 != nil || err2 != nil || err3 != nil {
+				return fmt.Errorf("bad arc line")
+			}
+			if !g.HasNode(u) || !g.HasNode(v) {
+				return ErrInvalidNodeIndex
+			}
+			g.AddEdge(NewEdge(u, v, w))
+		default:
+			return fmt.Errorf("unknown line %q", f[0])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if co == nil {
+		return g, nil
+	}
+
+	err = scanDIMACS(co, "co", func(line int, f []string) error {
+		switch f[0] {
+		case "p":
+		case "v":
+			if len(f) != 4 {
+				return fmt.Errorf("bad vertex line")
+			}
+			id, err1 := strconv.Atoi(f[1])
+			x, err2 := strconv.ParseFloat(f[2], 64)
+			y, err3 := strconv.ParseFloat(f[3], 64)
+			if err1 != nil || err2 != nil || err3 != nil {
+				return fmt.Errorf("bad vertex line")
+			}
+			return g.SetPosition(id, Position{X: x / DIMACSCoordScale, Y: y / DIMACSCoordScale})
+		default:
+			return fmt.Errorf("unknown line %q", f[0])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// scanDIMACS calls fn with the fields of every line which is not a comment.
+func scanDIMACS(r io.Reader, name string, fn func(line int, f []string) error) error {
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		f := strings.Fields(sc.Text())
+		if len(f) == 0 || f[0] == "c" {
+			continue
+		}
+		if err := fn(line, f); err != nil {
+			if err == ErrInvalidNodeIndex {
+				return fmt.Errorf("%w: %s line %d", err, name, line)
+			}
+			return fmt.Errorf("%w: %s line %d: %v", ErrMapFormat, name, line, err)
+		}
+	}
+	return sc.Err()
+}
+
+// LoadDIMACS reads the .gr file grFile and the .co file coFile, see
+// ReadDIMACS. coFile may be "".
+func LoadDIMACS(grFile, coFile string) (*Graph[int, int64], error) {
+	gr, err := os.Open(grFile)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+
+	if coFile == "" {
+		return ReadDIMACS(gr, nil)
+	}
+	co, err := os.Open(coFile)
+	if err != nil {
+		return nil, err
+	}
+	defer co.Close()
+
+	return ReadDIMACS(gr, co)
+}
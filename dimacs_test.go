@@ -0,0 +1,55 @@
+package graphalgo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testGr = `c 9th DIMACS Implementation Challenge
+p sp 3 3
+a 1 2 10
+a 2 3 20
+a 1 3 40
+`
+
+const testCo = `c coordinates
+p aux sp co 3
+v 1 -73530767 41085396
+v 2 -73530538 41086098
+v 3 -73519366 41048796
+`
+
+func TestReadDIMACS(t *testing.T) {
+	g, err := ReadDIMACS(strings.NewReader(testGr), strings.NewReader(testCo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.NumNodes() != 3 {
+		t.Fatalf("NumNodes got %d, want 3", g.NumNodes())
+	}
+	if idx, _ := g.Index(1); idx != 0 {
+		t.Errorf("index of node 1 got %d, want 0", idx)
+	}
+	pos, ok := g.Position(2)
+	if !ok || pos.X != -73.530538 || pos.Y != 41.086098 {
+		t.Errorf("position of node 2 got %v %v", pos, ok)
+	}
+
+	d := NewDijkstra(g, 1, 3)
+	d.Search()
+	p, err := d.PathToTarget()
+	if err != nil || p.Cost != 30 {
+		t.Errorf("path got %v %v, want cost 30", p, err)
+	}
+
+	if _, err := ReadDIMACS(strings.NewReader(testGr), nil); err != nil {
+		t.Error(err)
+	}
+	if _, err := ReadDIMACS(strings.NewReader("p sp 2 1\na 1 3 5\n"), nil); !errors.Is(err, ErrInvalidNodeIndex) {
+		t.Errorf("arc to node 3 got %v, want %v", err, ErrInvalidNodeIndex)
+	}
+	if _, err := ReadDIMACS(strings.NewReader("p sp 2 1\na 1 x 5\n"), nil); !errors.Is(err, ErrMapFormat) {
+		t.Errorf("bad arc got %v, want %v", err, ErrMapFormat)
+	}
+}
@@ -0,0 +1,142 @@
+package graphalgo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// DOTOptions configures Graph.WriteDOT.
+type DOTOptions[K comparable, W Weight] struct {
+	// Name is the name of the graph, "G" if empty.
+	Name string
+	// Undirected writes a "graph" with "--" edges. The graph must have every
+	// edge in both ways, only the first way is written.
+	Undirected bool
+
+	// Path is drawn in red if not nil.
+	Path *Path[K, W]
+	// Explored nodes are shaded, such as the ones Dijkstra.Explored returns.
+	Explored []K
+	// Frontier nodes are marked, such as the ones Dijkstra.Frontier returns.
+	Frontier []K
+}
+
+// WriteDOT writes g to w in Graphviz DOT language.
+// Nodes are labeled with their labels or IDs, placed at their positions if
+// they have, and their attributes are written as DOT attributes, but the
+// ones WriteDOT sets itself, such as label and color.
+// Edges are labeled with their costs. Nodes and edges are written in the
+// order they are added, so the same graph is always written the same way.
+func (g *Graph[K, W]) WriteDOT(w io.Writer, opts DOTOptions[K, W]) error {
+	bw := bufio.NewWriter(w)
+
+	name := opts.Name
+	if name == "" {
+		name = "G"
+	}
+	kind, arrow := "digraph", "->"
+	if opts.Undirected {
+		kind, arrow = "graph", "--"
+	}
+
+	explored := make(map[K]bool, len(opts.Explored))
+	for _, id := range opts.Explored {
+		explored[id] = true
+	}
+	frontier := make(map[K]bool, len(opts.Frontier))
+	for _, id := range opts.Frontier {
+		frontier[id] = true
+	}
+	onPath := make(map[K]bool)
+	pathEdges := make(map[[2]K]bool)
+	if opts.Path != nil {
+		for _, id := range opts.Path.Nodes {
+			onPath[id] = true
+		}
+		for _, e := range opts.Path.Edges {
+			pathEdges[[2]K{e.From, e.To}] = true
+			if opts.Undirected {
+				pathEdges[[2]K{e.To, e.From}] = true
+			}
+		}
+	}
+
+	fmt.Fprintf(bw, "%s %s {\n", kind, dotQuote(name))
+	g.walkNodes(func(_ int, n Node[K]) {
+		attrs := []string{"label=" + dotQuote(n.dotLabel())}
+		if n.HasPos {
+			attrs = append(attrs, fmt.Sprintf("pos=\"%g,%g!\"", n.Pos.X, n.Pos.Y))
+		}
+		switch {
+		case frontier[n.ID]:
+			attrs = append(attrs, "style=filled", "fillcolor=lightblue")
+		case explored[n.ID]:
+			attrs = append(attrs, "style=filled", "fillcolor=lightgray")
+		}
+		if onPath[n.ID] {
+			attrs = append(attrs, "color=red", "penwidth=2")
+		}
+		attrs = appendDOTAttrs(attrs, n.Attrs)
+		fmt.Fprintf(bw, "    %s [%s];\n", dotQuote(fmt.Sprint(n.ID)), strings.Join(attrs, ", "))
+	})
+
+	g.walkEdges(opts.Undirected, func(e Edge[K, W]) {
+		label := fmt.Sprint(e.Cost)
+		if e.Label != "" {
+			label = e.Label + "\n" + label
+		}
+		attrs := []string{"label=" + dotQuote(label)}
+		if pathEdges[[2]K{e.From, e.To}] {
+			attrs = append(attrs, "color=red", "penwidth=2")
+		}
+		attrs = appendDOTAttrs(attrs, e.Attrs)
+		fmt.Fprintf(bw, "    %s %s %s [%s];\n",
+			dotQuote(fmt.Sprint(e.From)), arrow, dotQuote(fmt.Sprint(e.To)), strings.Join(attrs, ", "))
+	})
+	bw.WriteString("}\n")
+
+	return bw.Flush()
+}
+
+func (n Node[K]) dotLabel() string {
+	if n.Label != "" {
+		return n.Label
+	}
+	return fmt.Sprint(n.ID)
+}
+
+// dotReservedAttrs are the DOT attributes which WriteDOT sets itself.
+var dotReservedAttrs = map[string]bool{"label": true, "pos": true, "style": true, "fillcolor": true, "color": true, "penwidth": true}
+
+// appendDOTAttrs appends a to attrs as DOT attributes, sorted by key.
+// Keys in dotReservedAttrs are skipped, so they do not clobber the ones
+// WriteDOT sets.
+func appendDOTAttrs(attrs []string, a Attrs) []string {
+	for _, k := range sortedAttrKeys(a) {
+		if dotReservedAttrs[k] {
+			continue
+		}
+		attrs = append(attrs, dotQuote(k)+"="+dotQuote(fmt.Sprint(a[k])))
+	}
+	return attrs
+}
+
+// dotQuote returns s as a quoted DOT ID.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+func sortedAttrKeys(a Attrs) []string {
+	keys := make([]string, 0, len(a))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
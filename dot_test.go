@@ -0,0 +1,62 @@
+package graphalgo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	g := newTestGraph()
+	g.SetLabel(0, "start")
+	g.SetAttr(0, "shape", "box")
+	g.SetAttr(0, "label", "clobbered")
+	g.SetAttr(0, "color", "green")
+
+	d := NewAstar(g, 0, 2)
+	d.Search()
+	p, _ := d.PathToTarget()
+
+	partial := NewDijkstra(g, 0, 2)
+	partial.Step(2)
+
+	var buf bytes.Buffer
+	err := g.WriteDOT(&buf, DOTOptions[int, float64]{
+		Name:     "a",
+		Path:     &p,
+		Explored: partial.Explored(),
+		Frontier: partial.Frontier(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+
+	for _, want := range []string{
+		"digraph \"a\" {\n",
+		`"0" [label="start", style=filled, fillcolor=lightgray, color=red, penwidth=2, "shape"="box"];`,
+		`"0" -> "4" [label="2.9"];`,
+		`"0" -> "5" [label="1", color=red, penwidth=2];`,
+		`"4" [label="4", style=filled, fillcolor=lightblue];`,
+		`"3" [label="3", style=filled, fillcolor=lightblue, color=red, penwidth=2];`,
+		`"5" [label="5", style=filled, fillcolor=lightgray, color=red, penwidth=2];`,
+		`"1" [label="1"];`,
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("DOT should contain %q, got\n%s", want, s)
+		}
+	}
+
+	if strings.Contains(s, "clobbered") || strings.Contains(s, "green") {
+		t.Errorf("DOT should skip attributes it sets itself, got\n%s", s)
+	}
+
+	ring := MapData{}
+	ring.Load("a2.map")
+	buf.Reset()
+	ring.Graph().WriteDOT(&buf, DOTOptions[int, float64]{Undirected: true})
+	s = buf.String()
+	if !strings.HasPrefix(s, "graph \"G\" {\n") || strings.Count(s, " -- ") != 10 {
+		t.Errorf("non-directional a2.map got\n%s", s)
+	}
+}
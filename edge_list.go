@@ -0,0 +1,127 @@
+package graphalgo
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// An edge list file has one edge per line, as "from,to,cost" in CSV,
+// "from<TAB>to<TAB>cost" in TSV, or "from to cost" separated by any spaces
+// and tabs as in SNAP .txt files. Node IDs are integers. The cost may be
+// left out, it is 1 then. A first line which does not start with two
+// integers is taken as a header and skipped. Lines starting with '#' are
+// comments.
+
+// ReadEdgeList reads an edge list from r, whose fields are separated by
+// comma, or by any run of spaces and tabs if comma is ' '. The edges are
+// added to the graph as they are read. If directed is false, every edge is
+// added both ways.
+func ReadEdgeList(r io.Reader, comma rune, directed bool) (*Graph[int, float64], error) {
+	var next func() ([]string, int, error)
+	if comma == ' ' {
+		next = fieldRecords(r)
+	} else {
+		next = csvRecords(r, comma)
+	}
+
+	g := NewGraph[int, float64]()
+	for first := true; ; first = false {
+		rec, line, err := next()
+		if err == io.EOF {
+			return g, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: edge list: %v", ErrMapFormat, err)
+		}
+
+		if len(rec) < 2 {
+			return nil, fmt.Errorf("%w: edge list line %d: %d fields", ErrMapFormat, line, len(rec))
+		}
+		from, err1 := strconv.Atoi(strings.TrimSpace(rec[0]))
+		to, err2 := strconv.Atoi(strings.TrimSpace(rec[1]))
+		if err1 != nil || err2 != nil {
+			if first {
+				continue // header
+			}
+			return nil, fmt.Errorf("%w: edge list line %d: bad node %q %q", ErrMapFormat, line, rec[0], rec[1])
+		}
+		cost := 1.0
+		if len(rec) > 2 && strings.TrimSpace(rec[2]) != "" {
+			cost, err = strconv.ParseFloat(strings.TrimSpace(rec[2]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: edge list line %d: bad cost %q", ErrMapFormat, line, rec[2])
+			}
+		}
+
+		e := NewEdge(from, to, cost)
+		g.AddEdge(e)
+		if !directed {
+			g.AddEdge(e.Reversed())
+		}
+	}
+}
+
+// csvRecords returns a function which reads the next record of r and its
+// line, or io.EOF after the last one.
+func csvRecords(r io.Reader, comma rune) func() ([]string, int, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	cr.TrimLeadingSpace = true
+	return func() ([]string, int, error) {
+		rec, err := cr.Read()
+		if err != nil {
+			return nil, 0, err
+		}
+		line, _ := cr.FieldPos(0)
+		return rec, line, nil
+	}
+}
+
+// fieldRecords is csvRecords for lines whose fields are separated by any
+// run of spaces and tabs. Blank lines are skipped.
+func fieldRecords(r io.Reader) func() ([]string, int, error) {
+	sc := bufio.NewScanner(r)
+	line := 0
+	return func() ([]string, int, error) {
+		for sc.Scan() {
+			line++
+			rec := strings.Fields(sc.Text())
+			if len(rec) > 0 && !strings.HasPrefix(rec[0], "#") {
+				return rec, line, nil
+			}
+		}
+		if err := sc.Err(); err != nil {
+			return nil, 0, err
+		}
+		return nil, 0, io.EOF
+	}
+}
+
+// LoadEdgeList reads the edge list file filename, see ReadEdgeList.
+// Files named *.tsv are separated by tab, *.txt by spaces and tabs, and
+// others by comma.
+func LoadEdgeList(filename string, directed bool) (*Graph[int, float64], error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	comma := ','
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".tsv":
+		comma = '\t'
+	case ".txt":
+		comma = ' '
+	}
+	return ReadEdgeList(f, comma, directed)
+}
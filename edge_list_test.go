@@ -0,0 +1,48 @@
+package graphalgo
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadEdgeList(t *testing.T) {
+	g, err := ReadEdgeList(strings.NewReader("from,to,cost\n0,1,2.5\n# comment\n1,2\n"), ',', true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.NumNodes() != 3 {
+		t.Fatalf("NumNodes got %d, want 3", g.NumNodes())
+	}
+	if e := g.EdgesFrom(0); len(e) != 1 || e[0].To != 1 || e[0].Cost != 2.5 {
+		t.Errorf("edges from 0 got %v", e)
+	}
+	if e := g.EdgesFrom(1); len(e) != 1 || e[0].Cost != 1 {
+		t.Errorf("edges from 1 got %v", e)
+	}
+
+	g, err = ReadEdgeList(strings.NewReader("0\t1\t3\n"), '\t', false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := g.EdgesFrom(1); len(e) != 1 || e[0].To != 0 || e[0].Cost != 3 {
+		t.Errorf("reverse edges from 1 got %v", e)
+	}
+
+	g, err = ReadEdgeList(strings.NewReader("# FromNodeId\tToNodeId\n0 1\n\n1  \t2   4\n"), ' ', true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := g.EdgesFrom(1); len(e) != 1 || e[0].To != 2 || e[0].Cost != 4 {
+		t.Errorf("edges from 1 separated by spaces got %v", e)
+	}
+	if _, err := ReadEdgeList(strings.NewReader("0 1\n2 x\n"), ' ', true); !errors.Is(err, ErrMapFormat) {
+		t.Errorf("bad node separated by spaces got %v, want %v", err, ErrMapFormat)
+	}
+
+	for _, s := range []string{"0,1\nx,2\n", "0,1,abc\n", "0\n"} {
+		if _, err := ReadEdgeList(strings.NewReader(s), ',', true); !errors.Is(err, ErrMapFormat) {
+			t.Errorf("%q got %v, want %v", s, err, ErrMapFormat)
+		}
+	}
+}
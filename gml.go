@@ -0,0 +1,355 @@
+package graphalgo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// gmlValue is a value in a GML file: an integer, a real, a string or a list.
+type gmlValue struct {
+	v    interface{} // int64, float64 or string, nil for a list
+	list []gmlPair
+}
+
+type gmlPair struct {
+	key   string
+	value gmlValue
+}
+
+type gmlParser struct {
+	r    *bufio.Reader
+	line int
+}
+
+// ReadGML reads a GML document from r.
+// It returns the graph and whether it is directed, GML graphs are
+// undirected unless they have "directed 1". Node IDs must be integers.
+// Nested lists other than graphics, such as LabelGraphics, are ignored.
+func ReadGML(r io.Reader) (*Graph[int, float64], bool, error) {
+	p := &gmlParser{r: bufio.NewReader(r), line: 1}
+	top, err := p.list(false)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var gr []gmlPair
+	graphs := 0
+	for _, kv := range top {
+		if kv.key == "graph" && kv.value.v == nil {
+			gr = kv.value.list
+			graphs++
+		}
+	}
+	if graphs != 1 {
+		return nil, false, fmt.Errorf("%w: %d graphs in one document", ErrUnsupportedConstruct, graphs)
+	}
+
+	directed := false
+	for _, kv := range gr {
+		if kv.key == "directed" {
+			v, err := gmlInt(kv)
+			if err != nil {
+				return nil, false, err
+			}
+			directed = v == 1
+		}
+	}
+
+	g := NewGraph[int, float64]()
+	var edges [][]gmlPair
+	for _, kv := range gr {
+		switch kv.key {
+		case "node":
+			n, err := gmlNode(kv.value.list)
+			if err != nil {
+				return nil, false, err
+			}
+			g.AddNode(n)
+		case "edge":
+			edges = append(edges, kv.value.list) // nodes may come after edges
+		case "hyperedge":
+			return nil, false, fmt.Errorf("%w: hyperedge", ErrUnsupportedConstruct)
+		}
+	}
+	for _, list := range edges {
+		m := make(map[string]interface{})
+		from, to := int64(math.MinInt64), int64(math.MinInt64)
+		edgeDirected := directed
+		for _, kv := range list {
+			var err error
+			switch {
+			case kv.key == "source":
+				from, err = gmlInt(kv)
+			case kv.key == "target":
+				to, err = gmlInt(kv)
+			case kv.key == "directed":
+				var v int64
+				v, err = gmlInt(kv)
+				edgeDirected = v == 1
+			case kv.key == "graph":
+				return nil, false, fmt.Errorf("%w: nested graph in edge", ErrUnsupportedConstruct)
+			case kv.key == "value":
+				m["cost"] = kv.value.v
+			case kv.value.v != nil:
+				m[kv.key] = kv.value.v
+			}
+			if err != nil {
+				return nil, false, err
+			}
+		}
+		if from == math.MinInt64 || to == math.MinInt64 {
+			return nil, false, fmt.Errorf("%w: edge without source or target", ErrMapFormat)
+		}
+		e := edgeFromValues(int(from), int(to), m)
+		g.AddEdge(e)
+		if !edgeDirected {
+			g.AddEdge(e.Reversed())
+		}
+	}
+	return g, directed, nil
+}
+
+func gmlNode(list []gmlPair) (Node[int], error) {
+	m := make(map[string]interface{})
+	id, hasID := int64(0), false
+	for _, kv := range list {
+		switch {
+		case kv.key == "id":
+			var err error
+			if id, err = gmlInt(kv); err != nil {
+				return Node[int]{}, err
+			}
+			hasID = true
+		case kv.key == "graph":
+			return Node[int]{}, fmt.Errorf("%w: nested graph in node %d", ErrUnsupportedConstruct, id)
+		case kv.key == "graphics":
+			for _, g := range kv.value.list {
+				if g.key == "x" || g.key == "y" || g.key == "z" {
+					m[g.key] = g.value.v
+				}
+			}
+		case kv.value.v != nil:
+			m[kv.key] = kv.value.v
+		}
+	}
+	if !hasID {
+		return Node[int]{}, fmt.Errorf("%w: node without id", ErrMapFormat)
+	}
+	return nodeFromValues(int(id), m), nil
+}
+
+// gmlInt returns the value of kv, which must be an integer. A real
+// without fraction, such as 2.0, is taken as one.
+func gmlInt(kv gmlPair) (int64, error) {
+	switch n := kv.value.v.(type) {
+	case int64:
+		return n, nil
+	case float64:
+		if n == math.Trunc(n) && math.Abs(n) < math.MaxInt64 {
+			return int64(n), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: gml %s is not an integer", ErrMapFormat, kv.key)
+}
+
+// list reads key value pairs until "]", or until the end if nested is false.
+func (p *gmlParser) list(nested bool) ([]gmlPair, error) {
+	var list []gmlPair
+	for {
+		key, err := p.token()
+		if err == io.EOF && !nested {
+			return list, nil
+		}
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		if key == "]" && nested {
+			return list, nil
+		}
+		if !isGMLKey(key) {
+			return nil, p.errorf("bad key %q", key)
+		}
+
+		tok, err := p.token()
+		if err != nil {
+			return nil, p.errorf("no value of %s", key)
+		}
+		var v gmlValue
+		switch {
+		case tok == "[":
+			v.list, err = p.list(true)
+			if err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(tok, "\""):
+			v.v = gmlUnescape(tok[1 : len(tok)-1])
+		default:
+			if i, err := strconv.ParseInt(tok, 10, 64); err == nil {
+				v.v = i
+			} else if f, err := strconv.ParseFloat(tok, 64); err == nil {
+				v.v = f
+			} else {
+				return nil, p.errorf("bad value %q of %s", tok, key)
+			}
+		}
+		list = append(list, gmlPair{key: key, value: v})
+	}
+}
+
+// token returns the next key, value, "[" or "]". Strings keep their quotes.
+func (p *gmlParser) token() (string, error) {
+	for {
+		c, _, err := p.r.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case c == '\n':
+			p.line++
+		case unicode.IsSpace(c):
+		case c == '#':
+			if _, err := p.r.ReadString('\n'); err != nil {
+				return "", err
+			}
+			p.line++
+		case c == '[' || c == ']':
+			return string(c), nil
+		case c == '"':
+			s, err := p.r.ReadString('"')
+			if err != nil {
+				return "", fmt.Errorf("unterminated string")
+			}
+			p.line += strings.Count(s, "\n")
+			return "\"" + s, nil
+		default:
+			var b strings.Builder
+			b.WriteRune(c)
+			for {
+				c, _, err := p.r.ReadRune()
+				if err != nil {
+					break
+				}
+				if unicode.IsSpace(c) || c == '[' || c == ']' || c == '"' {
+					p.r.UnreadRune()
+					break
+				}
+				b.WriteRune(c)
+			}
+			return b.String(), nil
+		}
+	}
+}
+
+func (p *gmlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: gml line %d: %s", ErrMapFormat, p.line, fmt.Sprintf(format, args...))
+}
+
+func isGMLKey(s string) bool {
+	for i, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || i > 0 && c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+var gmlEscaper = strings.NewReplacer("&", "&amp;", "\"", "&quot;")
+var gmlUnescaper = strings.NewReplacer("&quot;", "\"", "&amp;", "&")
+
+func gmlUnescape(s string) string {
+	return gmlUnescaper.Replace(s)
+}
+
+// WriteGML writes g to w as a GML document.
+// If directed is false, g must have every edge in both ways, and only the
+// first way is written. GML has no booleans, so true and false attributes
+// are written as 1 and 0; attribute names which are not GML keys are skipped.
+func WriteGML[W Weight](w io.Writer, g *Graph[int, W], directed bool) error {
+	bw := bufio.NewWriter(w)
+
+	writeValue := func(indent, key string, v interface{}) {
+		if !isGMLKey(key) {
+			return
+		}
+		var s string
+		switch v := v.(type) {
+		case bool:
+			s = "0"
+			if v {
+				s = "1"
+			}
+		case int, int8, int16, int32, int64:
+			s = fmt.Sprint(v)
+		case float32, float64:
+			s = gmlReal(v)
+		default:
+			s = "\"" + gmlEscaper.Replace(fmt.Sprint(v)) + "\""
+		}
+		fmt.Fprintf(bw, "%s%s %s\n", indent, key, s)
+	}
+	writeAttrs := func(attrs Attrs) {
+		keys := make([]string, 0, len(attrs))
+		for k := range attrs {
+			if !reservedAttrs[k] {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			writeValue("    ", k, attrs[k])
+		}
+	}
+
+	bw.WriteString("graph [\n")
+	if directed {
+		bw.WriteString("  directed 1\n")
+	} else {
+		bw.WriteString("  directed 0\n")
+	}
+	g.walkNodes(func(_ int, n Node[int]) {
+		bw.WriteString("  node [\n")
+		fmt.Fprintf(bw, "    id %d\n", n.ID)
+		if n.Label != "" {
+			writeValue("    ", "label", n.Label)
+		}
+		if n.HasPos {
+			bw.WriteString("    graphics [\n")
+			writeValue("      ", "x", n.Pos.X)
+			writeValue("      ", "y", n.Pos.Y)
+			if n.Pos.Z != 0 {
+				writeValue("      ", "z", n.Pos.Z)
+			}
+			bw.WriteString("    ]\n")
+		}
+		writeAttrs(n.Attrs)
+		bw.WriteString("  ]\n")
+	})
+	g.walkEdges(!directed, func(e Edge[int, W]) {
+		bw.WriteString("  edge [\n")
+		fmt.Fprintf(bw, "    source %d\n    target %d\n", e.From, e.To)
+		writeValue("    ", "cost", float64(e.Cost))
+		if e.Label != "" {
+			writeValue("    ", "label", e.Label)
+		}
+		writeAttrs(e.Attrs)
+		bw.WriteString("  ]\n")
+	})
+	bw.WriteString("]\n")
+
+	return bw.Flush()
+}
+
+// gmlReal formats a real so that it is not read back as an integer.
+func gmlReal(v interface{}) string {
+	s := formatValue(v)
+	if !strings.ContainsAny(s, ".eEnN") {
+		s += ".0"
+	}
+	return s
+}
@@ -0,0 +1,121 @@
+package graphalgo
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testGML = `# exported by Gephi
+graph [
+  directed 0
+  node [
+    id 1
+    label "Start"
+    graphics [ x 10.0 y -2.5 w 20 ]
+    terrain "mud"
+  ]
+  node [ id 2 ]
+  edge [ source 1 target 2 value 3.5 lanes 2 LabelGraphics [ text "x" ] ]
+  edge [ source 2 target 3 directed 1 ]
+]`
+
+func TestReadGML(t *testing.T) {
+	g, directed, err := ReadGML(strings.NewReader(testGML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if directed {
+		t.Error("directed got true, want false")
+	}
+	if g.NumNodes() != 3 {
+		t.Fatalf("NumNodes got %d, want 3", g.NumNodes())
+	}
+
+	n, _ := g.NodeByID(1)
+	if n.Label != "Start" || n.Pos != (Position{X: 10, Y: -2.5}) {
+		t.Errorf("node 1 got %+v", n)
+	}
+	if v, _ := Attr[string](n.Attrs, "terrain"); v != "mud" {
+		t.Errorf("terrain got %q, want mud", v)
+	}
+
+	e := g.EdgesFrom(1)
+	if len(e) != 1 || e[0].To != 2 || e[0].Cost != 3.5 {
+		t.Fatalf("edges from 1 got %v", e)
+	}
+	if v, _ := Attr[int64](e[0].Attrs, "lanes"); v != 2 {
+		t.Errorf("lanes got %d, want 2", v)
+	}
+	if len(g.EdgesFrom(2)) != 2 || len(g.EdgesFrom(3)) != 0 {
+		t.Errorf("edges from 2 got %v, from 3 got %v", g.EdgesFrom(2), g.EdgesFrom(3))
+	}
+}
+
+func TestGMLRoundTrip(t *testing.T) {
+	g := newTestGraph()
+	g.SetLabel(0, "say \"hi\"")
+	g.SetPosition(0, Position{X: 1, Y: 2})
+	g.SetAttr(0, "height", 3.0)
+	g.SetAttr(0, "open", true)
+
+	var buf bytes.Buffer
+	if err := WriteGML(&buf, g, true); err != nil {
+		t.Fatal(err)
+	}
+	g2, directed, err := ReadGML(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !directed {
+		t.Error("directed got false, want true")
+	}
+
+	n, _ := g2.NodeByID(0)
+	if n.Label != "say \"hi\"" || n.Pos != (Position{X: 1, Y: 2}) {
+		t.Errorf("node 0 got %+v", n)
+	}
+	if v, _ := Attr[float64](n.Attrs, "height"); v != 3 {
+		t.Errorf("height got %v, want 3", v)
+	}
+	if v, _ := Attr[int64](n.Attrs, "open"); v != 1 {
+		t.Errorf("open got %v, want 1", v)
+	}
+	for _, n := range g.Nodes() {
+		e, e2 := g.EdgesFrom(n.ID), g2.EdgesFrom(n.ID)
+		if len(e) != len(e2) {
+			t.Fatalf("edges from %d got %v, want %v", n.ID, e2, e)
+		}
+		for i := range e {
+			if e[i].To != e2[i].To || e[i].Cost != e2[i].Cost {
+				t.Errorf("edge got %v, want %v", e2[i], e[i])
+			}
+		}
+	}
+}
+
+func TestReadGMLErrors(t *testing.T) {
+	for _, doc := range []string{
+		`graph [ node [ id 1 graph [ ] ] ]`,
+		`graph [ hyperedge [ ] ]`,
+		`graph [ ] graph [ ]`,
+	} {
+		if _, _, err := ReadGML(strings.NewReader(doc)); !errors.Is(err, ErrUnsupportedConstruct) {
+			t.Errorf("%s got %v, want %v", doc, err, ErrUnsupportedConstruct)
+		}
+	}
+	for _, doc := range []string{
+		`graph [ node [ id 1 ]`,
+		`graph [ node [ label "x" ] ]`,
+		`graph [ edge [ source 1 ] ]`,
+		`graph [ node [ id "a" ] ]`,
+		`graph [ node [ id 1.5 ] ]`,
+		`graph [ node [ id 1 ] edge [ source "x" target 1 ] ]`,
+		`graph [ directed [ ] ]`,
+	} {
+		if _, _, err := ReadGML(strings.NewReader(doc)); !errors.Is(err, ErrMapFormat) {
+			t.Errorf("%s got %v, want %v", doc, err, ErrMapFormat)
+		}
+	}
+}
@@ -1,189 +1,333 @@
-package main
+package graphalgo
 
 import (
 	"errors"
 	"fmt"
-	"github.com/ZhangGuangxu/circularqueue"
-	"github.com/ZhangGuangxu/stack"
 )
 
-var errInvalidNodeIndex = errors.New("invalid node index")
-var errPathNotFound = errors.New("path not found")
-var errEdgeTypeWrong = errors.New("edge type wrong")
+// ErrInvalidNodeIndex tells us some node index is out of the graph.
+var ErrInvalidNodeIndex = errors.New("invalid node index")
 
-type graphEdges []graphEdge
+// ErrPathNotFound tells us there is no path between the two nodes.
+var ErrPathNotFound = errors.New("path not found")
 
-type graph struct {
-	nodes     []graphNode
-	edges     []graphEdges
-	nextIndex int
-}
+// ErrEdgeTypeWrong tells us an item popped from a container is not an Edge.
+var ErrEdgeTypeWrong = errors.New("edge type wrong")
 
-func newGraph() *graph {
-	return &graph{}
-}
+// Edges is the adjacency list of one node.
+type Edges[K comparable, W Weight] []Edge[K, W]
 
-func (g *graph) addNode(n graphNode) {
-	if n.Index != g.nextIndex {
-		return
-	}
+// Graph is a sparse graph stored as adjacency lists.
+// Nodes are keyed by user IDs of type K. Every node is also given
+// a dense index, in the order nodes are added, starting from 0.
+type Graph[K comparable, W Weight] struct {
+	nodes []Node[K]
+	edges []Edges[K, W]
+	index map[K]int // key is node ID, value is dense index
 
-	g.nodes = append(g.nodes, n)
-	g.nextIndex++
+	src graphSource[K, W] // read-only backing such as a mapped binary file, nil when loaded
+}
 
-	g.edges = append(g.edges, graphEdges{})
+// graphSource is a read-only graph which is not in the fields of Graph,
+// such as a memory-mapped binary file. Graph reads nodes and edges from
+// it one by one, and copies it into memory by load before anything
+// which needs all nodes or changes the graph.
+type graphSource[K comparable, W Weight] interface {
+	numNodes() int
+	index(id K) (int, bool)
+	node(idx int) Node[K]
+	edgesFrom(idx int) Edges[K, W]
 }
 
-func (g *graph) addEdge(e graphEdge) {
-	if !isValidNodeIndex(e.From) || !isValidNodeIndex(e.To) {
-		return
-	}
-	if e.From >= len(g.nodes) {
-		return
+// NewGraph returns an empty graph.
+func NewGraph[K comparable, W Weight]() *Graph[K, W] {
+	return &Graph[K, W]{
+		index: make(map[K]int),
 	}
-
-	g.edges[e.From] = append(g.edges[e.From], e)
 }
 
-func (g *graph) show() {
-	for i, n := range g.nodes {
-		if !isValidNodeIndex(n.Index) {
-			continue
+// AddNode adds n to the graph and returns its dense index.
+// If a node with the same ID exists, it gets the position, label and
+// attributes which n has, other things of it are not changed.
+func (g *Graph[K, W]) AddNode(n Node[K]) int {
+	g.load()
+	if idx, ok := g.index[n.ID]; ok {
+		old := &g.nodes[idx]
+		if n.HasPos {
+			old.Pos = n.Pos
+			old.HasPos = true
 		}
-		fmt.Printf("%d-> ", n.Index)
-		for _, edge := range g.edges[i] {
-			//fmt.Printf("f:%d, t:%d, c:%v; ", edge.From, edge.To, edge.Cost)
-			fmt.Printf("%v; ", edge)
+		if n.Label != "" {
+			old.Label = n.Label
 		}
-		fmt.Println()
+		for k, v := range n.Attrs {
+			old.Attrs = old.Attrs.With(k, v)
+		}
+		return idx
 	}
-	fmt.Println()
+
+	n.Index = len(g.nodes)
+	g.nodes = append(g.nodes, n)
+	g.edges = append(g.edges, Edges[K, W]{})
+	g.index[n.ID] = n.Index
+	return n.Index
 }
 
-// dft is deep first traverse.
-func (g *graph) dft() {
+// AddEdge adds e to the adjacency list of e.From.
+// Nodes e.From and e.To are added first if they are not in the graph.
+func (g *Graph[K, W]) AddEdge(e Edge[K, W]) {
+	g.load()
+	from := g.AddNode(NewNode(e.From))
+	g.AddNode(NewNode(e.To))
 
+	g.edges[from] = append(g.edges[from], e)
 }
 
-func reversePath(path []graphEdge) []graphEdge {
-	length := len(path)
-	half := length / 2
-	for i := 0; i < half; i++ {
-		j := length - (i + 1)
-		path[i], path[j] = path[j], path[i]
+// RemoveEdge removes the edges from node from to node to, and returns
+// how many are removed.
+func (g *Graph[K, W]) RemoveEdge(from, to K) int {
+	g.load()
+	idx, ok := g.index[from]
+	if !ok {
+		return 0
 	}
-	return path
-}
 
-// dfs is deep first search.
-func (g *graph) dfs(begin graphNode, end graphNode) ([]graphEdge, error) {
-	b := begin.Index
-	if b < 0 || b >= len(g.nodes) {
-		return nil, errInvalidNodeIndex
+	edges := make(Edges[K, W], 0, len(g.edges[idx])) // slices returned by EdgesFrom stay as they are
+	for _, e := range g.edges[idx] {
+		if e.To != to {
+			edges = append(edges, e)
+		}
 	}
+	n := len(g.edges[idx]) - len(edges)
+	g.edges[idx] = edges
+	return n
+}
 
-	e := end.Index
-	if e < 0 || e >= len(g.nodes) {
-		return nil, errInvalidNodeIndex
+// HasNode returns true if node id is in the graph.
+func (g *Graph[K, W]) HasNode(id K) bool {
+	_, ok := g.Index(id)
+	return ok
+}
+
+// Index returns the dense index of node id.
+func (g *Graph[K, W]) Index(id K) (int, bool) {
+	if g.src != nil {
+		return g.src.index(id)
 	}
+	idx, ok := g.index[id]
+	return idx, ok
+}
 
-	if b == e {
-		return []graphEdge{}, nil
+// Node returns the node whose dense index is idx.
+func (g *Graph[K, W]) Node(idx int) (Node[K], error) {
+	if idx < 0 || idx >= g.NumNodes() {
+		return Node[K]{Index: InvalidNodeIndex}, ErrInvalidNodeIndex
+	}
+	if g.src != nil {
+		return g.src.node(idx), nil
 	}
+	return g.nodes[idx], nil
+}
 
-	s := stack.NewStack()
-	for _, tmp := range g.edges[b] {
-		s.Push(tmp)
+// Position returns the position of node id.
+// The bool is false if the node does not exist or has no position.
+func (g *Graph[K, W]) Position(id K) (Position, bool) {
+	n, ok := g.NodeByID(id)
+	if !ok || !n.HasPos {
+		return Position{}, false
 	}
-	record := make(map[int]int) // 用于记录曾经加入过栈的边，key是To, value是From
-	record[b] = b               // 起始点比较特殊
+	return n.Pos, true
+}
 
-	for !s.IsEmpty() {
-		tmpEdge, err := s.Pop()
-		if err != nil {
-			return nil, err
-		}
-		edge, ok := tmpEdge.(graphEdge)
-		if !ok {
-			return nil, errEdgeTypeWrong
-		}
+// SetPosition sets the position of node id.
+func (g *Graph[K, W]) SetPosition(id K, pos Position) error {
+	g.load()
+	idx, ok := g.index[id]
+	if !ok {
+		return ErrInvalidNodeIndex
+	}
+	g.nodes[idx].Pos = pos
+	g.nodes[idx].HasPos = true
+	return nil
+}
 
-		if edge.To == e {
-			var path []graphEdge
-			for {
-				path = append(path, edge)
-				if edge.From == b {
-					return reversePath(path), nil
-				}
+// NodeByID returns node id.
+func (g *Graph[K, W]) NodeByID(id K) (Node[K], bool) {
+	idx, ok := g.Index(id)
+	if !ok {
+		return Node[K]{Index: InvalidNodeIndex}, false
+	}
+	if g.src != nil {
+		return g.src.node(idx), true
+	}
+	return g.nodes[idx], true
+}
 
-				edge = graphEdge{From: record[edge.From], To: edge.From}
-			}
-		}
+// SetLabel sets the label of node id.
+func (g *Graph[K, W]) SetLabel(id K, label string) error {
+	g.load()
+	idx, ok := g.index[id]
+	if !ok {
+		return ErrInvalidNodeIndex
+	}
+	g.nodes[idx].Label = label
+	return nil
+}
 
-		if _, ok := record[edge.To]; ok {
-			continue
-		}
-		for _, tmp := range g.edges[edge.To] {
-			s.Push(tmp)
-		}
-		record[edge.To] = edge.From
+// SetAttr sets attribute key of node id to value.
+func (g *Graph[K, W]) SetAttr(id K, key string, value interface{}) error {
+	g.load()
+	idx, ok := g.index[id]
+	if !ok {
+		return ErrInvalidNodeIndex
 	}
+	g.nodes[idx].Attrs = g.nodes[idx].Attrs.With(key, value)
+	return nil
+}
 
-	return nil, errPathNotFound
+// Nodes returns all nodes ordered by dense index.
+// The returned slice must not be modified.
+// For a graph opened from a binary file it is read from the file on every
+// call, use NumNodes and Node to walk such a graph.
+func (g *Graph[K, W]) Nodes() []Node[K] {
+	if g.src == nil {
+		return g.nodes
+	}
+	nodes := make([]Node[K], g.src.numNodes())
+	for i := range nodes {
+		nodes[i] = g.src.node(i)
+	}
+	return nodes
 }
 
-func (g *graph) bfs(begin graphNode, end graphNode) ([]graphEdge, error) {
-	b := begin.Index
-	if b < 0 || b >= len(g.nodes) {
-		return nil, errInvalidNodeIndex
+// NumNodes returns the number of nodes in the graph.
+func (g *Graph[K, W]) NumNodes() int {
+	if g.src != nil {
+		return g.src.numNodes()
 	}
+	return len(g.nodes)
+}
 
-	e := end.Index
-	if e < 0 || e >= len(g.nodes) {
-		return nil, errInvalidNodeIndex
+// EdgesFrom returns the outgoing edges of node id.
+func (g *Graph[K, W]) EdgesFrom(id K) Edges[K, W] {
+	idx, ok := g.Index(id)
+	if !ok {
+		return nil
+	}
+	if g.src != nil {
+		return g.src.edgesFrom(idx)
 	}
+	return g.edges[idx]
+}
 
-	if b == e {
-		return []graphEdge{}, nil
+// edgesAt returns the outgoing edges of the node whose dense index is idx.
+func (g *Graph[K, W]) edgesAt(idx int) Edges[K, W] {
+	if g.src != nil {
+		return g.src.edgesFrom(idx)
 	}
+	return g.edges[idx]
+}
 
-	q := circularqueue.NewCircularQueue()
-	for _, tmp := range g.edges[b] {
-		q.Push(tmp)
+// walkNodes calls fn with every node ordered by dense index.
+// Unlike Nodes, it does not copy a graph opened from a binary file.
+func (g *Graph[K, W]) walkNodes(fn func(idx int, n Node[K])) {
+	for i := 0; i < g.NumNodes(); i++ {
+		n, _ := g.Node(i)
+		fn(i, n)
 	}
-	record := make(map[int]int)
-	record[b] = b
+}
 
-	for !q.IsEmpty() {
-		tmp, err := q.Pop()
-		if err != nil {
-			return nil, err
-		}
-		edge, ok := tmp.(graphEdge)
-		if !ok {
-			return nil, errEdgeTypeWrong
+// Reverse returns the graph with the same nodes, in the same order, and
+// every edge turned around.
+func (g *Graph[K, W]) Reverse() *Graph[K, W] {
+	rg := NewGraph[K, W]()
+	g.walkNodes(func(_ int, n Node[K]) {
+		rg.AddNode(n)
+	})
+	for i := 0; i < g.NumNodes(); i++ {
+		for _, e := range g.edgesAt(i) {
+			rg.AddEdge(e.Reversed())
 		}
+	}
+	return rg
+}
 
-		if edge.To == e {
-			var path []graphEdge
-			for {
-				path = append(path, edge)
-				if edge.From == b {
-					return reversePath(path), nil
+// walkEdges calls fn with every edge in the order they are added.
+// If undirected is true, g must have every edge in both ways, and fn is
+// called with the first way only.
+func (g *Graph[K, W]) walkEdges(undirected bool, fn func(e Edge[K, W])) {
+	walked := make(map[[2]K]int) // key is {From, To}, value is how many times it is walked
+	for i := 0; i < g.NumNodes(); i++ {
+		for _, e := range g.edgesAt(i) {
+			if undirected {
+				if walked[[2]K{e.To, e.From}] > 0 {
+					walked[[2]K{e.To, e.From}]--
+					continue
 				}
-
-				edge = graphEdge{From: record[edge.From], To: edge.From}
+				walked[[2]K{e.From, e.To}]++
 			}
+			fn(e)
 		}
+	}
+}
 
-		if _, ok := record[edge.To]; ok {
-			continue
-		}
-		for _, tmp := range g.edges[edge.To] {
-			q.Push(tmp)
+// load copies the nodes and edges of g.src into memory and drops g.src.
+func (g *Graph[K, W]) load() {
+	if g.src == nil {
+		return
+	}
+	src := g.src
+	g.src = nil
+
+	n := src.numNodes()
+	g.nodes = make([]Node[K], n)
+	g.edges = make([]Edges[K, W], n)
+	g.index = make(map[K]int, n)
+	for i := 0; i < n; i++ {
+		g.nodes[i] = src.node(i)
+		g.edges[i] = src.edgesFrom(i)
+		g.index[g.nodes[i].ID] = i
+	}
+}
+
+// Show prints every node with its outgoing edges.
+func (g *Graph[K, W]) Show() {
+	g.walkNodes(func(i int, n Node[K]) {
+		fmt.Printf("%v-> ", n.ID)
+		for _, edge := range g.edgesAt(i) {
+			//fmt.Printf("f:%v, t:%v, c:%v; ", edge.From, edge.To, edge.Cost)
+			fmt.Printf("%v; ", edge)
 		}
-		record[edge.To] = edge.From
+		fmt.Println()
+	})
+	fmt.Println()
+}
+
+// dft is deep first traverse.
+func (g *Graph[K, W]) dft() {
+
+}
+
+func reversePath[K comparable, W Weight](path []Edge[K, W]) []Edge[K, W] {
+	length := len(path)
+	half := length / 2
+	for i := 0; i < half; i++ {
+		j := length - (i + 1)
+		path[i], path[j] = path[j], path[i]
 	}
+	return path
+}
+
+// DFS is deep first search from node b to node e.
+func (g *Graph[K, W]) DFS(b K, e K) (Path[K, W], error) {
+	s := NewDFS(g, b, e)
+	s.Search()
+	return s.PathToTarget()
+}
 
-	return nil, errPathNotFound
+// BFS is breadth first search from node b to node e.
+func (g *Graph[K, W]) BFS(b K, e K) (Path[K, W], error) {
+	s := NewBFS(g, b, e)
+	s.Search()
+	return s.PathToTarget()
 }
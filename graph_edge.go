@@ -1,15 +1,39 @@
-package main
+package graphalgo
 
-type graphEdge struct {
-	From int
-	To   int
-	Cost float32
+// Edge is a directed edge from node From to node To.
+// From and To are node IDs.
+type Edge[K comparable, W Weight] struct {
+	From  K
+	To    K
+	Cost  W
+	Label string
+	Attrs Attrs
 }
 
-func newGraphEdgeDefault() graphEdge {
-	return graphEdge{From: invalidNodeIndex, To: invalidNodeIndex, Cost: 1.0}
+// NewEdgeDefault returns an edge between zero IDs with cost 1.0.
+func NewEdgeDefault[K comparable, W Weight]() Edge[K, W] {
+	return Edge[K, W]{Cost: 1.0}
 }
 
-func newGraphEdge(f int, t int, c float32) graphEdge {
-	return graphEdge{From: f, To: t, Cost: c}
+// NewEdge returns an edge from f to t with cost c.
+func NewEdge[K comparable, W Weight](f K, t K, c W) Edge[K, W] {
+	return Edge[K, W]{From: f, To: t, Cost: c}
+}
+
+// WithLabel returns a copy of e with label l.
+func (e Edge[K, W]) WithLabel(l string) Edge[K, W] {
+	e.Label = l
+	return e
+}
+
+// WithAttr returns a copy of e with attribute key set to value.
+func (e Edge[K, W]) WithAttr(key string, value interface{}) Edge[K, W] {
+	e.Attrs = e.Attrs.With(key, value)
+	return e
+}
+
+// Reversed returns e turned around, with its cost, label and attributes kept.
+func (e Edge[K, W]) Reversed() Edge[K, W] {
+	e.From, e.To = e.To, e.From
+	return e
 }
@@ -1,17 +1,57 @@
-package main
+package graphalgo
 
 const (
-	invalidNodeIndex = -1
+	// InvalidNodeIndex marks a node index which refers to no node.
+	InvalidNodeIndex = -1
 )
 
-func isValidNodeIndex(idx int) bool {
+// IsValidNodeIndex returns true if idx may refer to a node.
+func IsValidNodeIndex(idx int) bool {
 	return idx >= 0
 }
 
-type graphNode struct {
-	Index int
+// Position is the coordinate of a node. Z is 0 on 2D maps.
+type Position struct {
+	X float64
+	Y float64
+	Z float64
 }
 
-func newGraphNode(idx int) graphNode {
-	return graphNode{Index: idx}
+// Node is a graph node.
+// ID is given by user, Index is the dense index given by the graph
+// when the node is added. Pos is valid only if HasPos is true.
+type Node[K comparable] struct {
+	ID     K
+	Index  int
+	Pos    Position
+	HasPos bool
+	Label  string
+	Attrs  Attrs
+}
+
+// NewNode returns a node with id, which is not added to any graph yet.
+func NewNode[K comparable](id K) Node[K] {
+	return Node[K]{ID: id, Index: InvalidNodeIndex}
+}
+
+// NewNodeAt returns a node with id at 2D position (x, y).
+func NewNodeAt[K comparable](id K, x, y float64) Node[K] {
+	return Node[K]{ID: id, Index: InvalidNodeIndex, Pos: Position{X: x, Y: y}, HasPos: true}
+}
+
+// NewNodeAt3D returns a node with id at 3D position (x, y, z).
+func NewNodeAt3D[K comparable](id K, x, y, z float64) Node[K] {
+	return Node[K]{ID: id, Index: InvalidNodeIndex, Pos: Position{X: x, Y: y, Z: z}, HasPos: true}
+}
+
+// WithLabel returns a copy of n with label l.
+func (n Node[K]) WithLabel(l string) Node[K] {
+	n.Label = l
+	return n
+}
+
+// WithAttr returns a copy of n with attribute key set to value.
+func (n Node[K]) WithAttr(key string, value interface{}) Node[K] {
+	n.Attrs = n.Attrs.With(key, value)
+	return n
 }
@@ -1,4 +1,4 @@
-package main
+package graphalgo
 
 import (
 	"testing"
@@ -6,11 +6,168 @@ import (
 
 // TestGraph tests graph
 func TestGraph(t *testing.T) {
-	g := newGraph()
-	g.addNode(newGraphNode(0))
-	g.addEdge(newGraphEdge(0, 4, 2.9))
-	g.addEdge(newGraphEdge(0, 5, 1.0))
-	g.addNode(newGraphNode(1))
-	g.addEdge(newGraphEdge(1, 2, 3.1))
-	g.show()
+	g := NewGraph[int, float64]()
+	g.AddNode(NewNode(0))
+	g.AddEdge(NewEdge(0, 4, 2.9))
+	g.AddEdge(NewEdge(0, 5, 1.0))
+	g.AddNode(NewNode(1))
+	g.AddEdge(NewEdge(1, 2, 3.1))
+	g.Show()
+}
+
+// TestGraphID tests graph with IDs which are not dense indices.
+func TestGraphID(t *testing.T) {
+	g := NewGraph[string, float64]()
+	g.AddEdge(NewEdge("b", "x", 1.0))
+	g.AddEdge(NewEdge("x", "a", 2.0))
+	g.AddEdge(NewEdge("b", "a", 5.0))
+	g.AddNode(NewNode("alone"))
+
+	if g.NumNodes() != 4 {
+		t.Errorf("g.NumNodes() got %d, want %d", g.NumNodes(), 4)
+	}
+	if idx, ok := g.Index("a"); !ok || idx != 2 {
+		t.Errorf("g.Index(a) got %d %v, want %d true", idx, ok, 2)
+	}
+
+	path, err := g.BFS("b", "a")
+	if err != nil || len(path.Edges) != 1 {
+		t.Errorf("g.BFS(b, a) got %v %v, want one edge", path, err)
+	}
+	if _, err := g.DFS("b", "alone"); err != ErrPathNotFound {
+		t.Errorf("g.DFS(b, alone) got %v, want %v", err, ErrPathNotFound)
+	}
+	if _, err := g.DFS("b", "nobody"); err != ErrInvalidNodeIndex {
+		t.Errorf("g.DFS(b, nobody) got %v, want %v", err, ErrInvalidNodeIndex)
+	}
+
+	d := NewDijkstra(g, "b", "a")
+	d.Search()
+	path, err = d.PathToTarget()
+	if err != nil || len(path.Edges) != 2 || path.Nodes[1] != "x" || path.Cost != 3.0 {
+		t.Errorf("Dijkstra b->a got %v %v, want b->x->a", path, err)
+	}
+
+	a := NewAstar(g, "b", "a")
+	a.Search()
+	path, err = a.PathToTarget()
+	if err != nil || len(path.Edges) != 2 || path.Edges[1].From != "x" || !path.Optimal {
+		t.Errorf("Astar b->a got %v %v, want b->x->a", path, err)
+	}
+}
+
+// TestGraphSparseIndex tests loading nodes whose IDs are not 0..N.
+func TestGraphSparseIndex(t *testing.T) {
+	md := MapData{EdgesMap: EdgesMap{
+		100: MapEdges{7: MapEdge{Cost: 1.0}},
+		7:   MapEdges{42: MapEdge{Cost: 1.0}},
+	}}
+	g := md.Graph()
+	if g.NumNodes() != 3 {
+		t.Errorf("g.NumNodes() got %d, want %d", g.NumNodes(), 3)
+	}
+	path, err := g.BFS(100, 42)
+	if err != nil || len(path.Edges) != 2 {
+		t.Errorf("g.BFS(100, 42) got %v %v, want two edges", path, err)
+	}
+}
+
+// TestRemoveEdge tests removing edges and searching without them.
+func TestRemoveEdge(t *testing.T) {
+	g := newTestGraph()
+	before := g.EdgesFrom(0)
+	if n := g.RemoveEdge(0, 5); n != 1 {
+		t.Fatalf("RemoveEdge(0, 5) got %d, want 1", n)
+	}
+	if n := g.RemoveEdge(0, 5); n != 0 {
+		t.Errorf("RemoveEdge(0, 5) again got %d, want 0", n)
+	}
+	if len(g.EdgesFrom(0)) != len(before)-1 {
+		t.Errorf("edges from 0 got %v", g.EdgesFrom(0))
+	}
+	if g.RemoveEdge(100, 0) != 0 {
+		t.Error("RemoveEdge from a missing node removed something")
+	}
+
+	for _, e := range g.EdgesFrom(0) {
+		g.RemoveEdge(0, e.To)
+	}
+	if _, err := g.BFS(0, 2); err != ErrPathNotFound {
+		t.Errorf("BFS without edges from 0 got %v, want %v", err, ErrPathNotFound)
+	}
+}
+
+func TestReverse(t *testing.T) {
+	g := newTestGraph()
+	rg := g.Reverse()
+	if rg.NumNodes() != g.NumNodes() {
+		t.Fatalf("reverse has %d nodes, want %d", rg.NumNodes(), g.NumNodes())
+	}
+	for i, n := range g.Nodes() {
+		if rn, _ := rg.Node(i); rn.ID != n.ID {
+			t.Errorf("reverse node %d got %v, want %v", i, rn.ID, n.ID)
+		}
+		for _, e := range g.EdgesFrom(n.ID) {
+			found := false
+			for _, re := range rg.EdgesFrom(e.To) {
+				found = found || (re.To == n.ID && re.Cost == e.Cost)
+			}
+			if !found {
+				t.Errorf("reverse misses edge %v", e.Reversed())
+			}
+		}
+	}
+}
+
+// TestAttrs tests node and edge attributes and cost functions reading them.
+func TestAttrs(t *testing.T) {
+	g := NewGraph[string, float64]()
+	door := NewEdge("hall", "room", 1.0).WithLabel("door").WithAttr("locked", true)
+	g.AddEdge(door)
+	g.AddEdge(NewEdge("hall", "yard", 1.0))
+	g.AddEdge(NewEdge("yard", "room", 1.0))
+	g.AddNode(NewNodeAt("yard", 3, 4).WithLabel("Yard").WithAttr("terrain", "mud"))
+
+	if n, ok := g.NodeByID("yard"); !ok || n.Label != "Yard" || !n.HasPos {
+		t.Errorf("g.NodeByID(yard) got %v %v", n, ok)
+	}
+	if v, ok := Attr[string](g.Nodes()[2].Attrs, "terrain"); !ok || v != "mud" {
+		t.Errorf("terrain of yard got %v %v, want mud", v, ok)
+	}
+	if _, ok := Attr[int](g.Nodes()[2].Attrs, "terrain"); ok {
+		t.Error("terrain of yard is not an int")
+	}
+	if err := g.SetAttr("room", "dark", true); err != nil {
+		t.Error(err)
+	}
+	if err := g.SetLabel("nowhere", "x"); err != ErrInvalidNodeIndex {
+		t.Errorf("g.SetLabel(nowhere) got %v, want %v", err, ErrInvalidNodeIndex)
+	}
+
+	other := door.WithAttr("locked", false)
+	if locked, _ := Attr[bool](door.Attrs, "locked"); !locked {
+		t.Error("WithAttr should not change the edge it copies")
+	}
+	if r := other.Reversed(); r.From != "room" || r.Label != "door" {
+		t.Errorf("other.Reversed() got %v", r)
+	}
+
+	cost := func(e Edge[string, float64]) (float64, bool) {
+		if locked, _ := Attr[bool](e.Attrs, "locked"); locked {
+			return 0, false
+		}
+		n, _ := g.NodeByID(e.To)
+		if terrain, _ := Attr[string](n.Attrs, "terrain"); terrain == "mud" {
+			return e.Cost * 3, true
+		}
+		return e.Cost, true
+	}
+	for _, a := range []Algorithm{AlgorithmDijkstra, AlgorithmAstar} {
+		s, _ := NewSearcher(a, g, "hall", "room", SearchOptions[string, float64]{Cost: cost})
+		s.Search()
+		p, err := s.PathToTarget()
+		if err != nil || p.Hops() != 2 || p.Cost != 4.0 {
+			t.Errorf("%s hall->room got %v %v, want hall->yard->room cost 4", a, p, err)
+		}
+	}
 }
@@ -0,0 +1,369 @@
+package graphalgo
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedConstruct tells us a GraphML or GML file has something
+// which can not be mapped onto Graph, such as hyperedges or nested graphs.
+// The returned error wraps it with the name of the construct.
+var ErrUnsupportedConstruct = errors.New("unsupported construct")
+
+// GraphML and GML files map onto Graph like this.
+// Node data "label" is the label, "x", "y" and "z" are the position.
+// Edge data "cost" or "weight" is the cost, which is 1 if missing, and
+// "label" is the label. Other data become attributes. Undirected edges
+// are added both ways. These names are reserved, attributes with them
+// are not written.
+
+type graphmlDoc struct {
+	XMLName xml.Name       `xml:"graphml"`
+	Keys    []graphmlKey   `xml:"key"`
+	Graphs  []graphmlGraph `xml:"graph"`
+}
+
+type graphmlKey struct {
+	ID      string  `xml:"id,attr"`
+	For     string  `xml:"for,attr"`
+	Name    string  `xml:"attr.name,attr"`
+	Type    string  `xml:"attr.type,attr"`
+	Default *string `xml:"default"`
+}
+
+type graphmlGraph struct {
+	ID          string             `xml:"id,attr"`
+	EdgeDefault string             `xml:"edgedefault,attr"`
+	Nodes       []graphmlNode      `xml:"node"`
+	Edges       []graphmlEdge      `xml:"edge"`
+	Hyperedges  []graphmlHyperedge `xml:"hyperedge"`
+}
+
+type graphmlNode struct {
+	ID     string         `xml:"id,attr"`
+	Data   []graphmlData  `xml:"data"`
+	Graphs []graphmlGraph `xml:"graph"`
+	Ports  []graphmlPort  `xml:"port"`
+}
+
+type graphmlEdge struct {
+	Source   string         `xml:"source,attr"`
+	Target   string         `xml:"target,attr"`
+	Directed string         `xml:"directed,attr"`
+	Data     []graphmlData  `xml:"data"`
+	Graphs   []graphmlGraph `xml:"graph"`
+}
+
+type graphmlHyperedge struct{}
+
+type graphmlPort struct{}
+
+type graphmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// ReadGraphML reads a GraphML document from r.
+// It returns the graph and whether its edges are directed by default.
+func ReadGraphML(r io.Reader) (*Graph[string, float64], bool, error) {
+	var doc graphmlDoc
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, false, err
+	}
+	if len(doc.Graphs) != 1 {
+		return nil, false, fmt.Errorf("%w: %d graphs in one document", ErrUnsupportedConstruct, len(doc.Graphs))
+	}
+	gr := doc.Graphs[0]
+	if len(gr.Hyperedges) > 0 {
+		return nil, false, fmt.Errorf("%w: hyperedge", ErrUnsupportedConstruct)
+	}
+
+	keys := make(map[string]graphmlKey, len(doc.Keys))
+	for _, k := range doc.Keys {
+		if k.Name == "" {
+			k.Name = k.ID
+		}
+		keys[k.ID] = k
+	}
+	// data of a node or an edge, with the defaults of the keys for it
+	values := func(kind string, data []graphmlData) (map[string]interface{}, error) {
+		m := make(map[string]interface{})
+		for _, k := range doc.Keys {
+			if k.Default != nil && (k.For == kind || k.For == "all") {
+				v, err := graphmlValue(k, *k.Default)
+				if err != nil {
+					return nil, err
+				}
+				m[keys[k.ID].Name] = v
+			}
+		}
+		for _, d := range data {
+			k, ok := keys[d.Key]
+			if !ok {
+				k = graphmlKey{ID: d.Key, Name: d.Key}
+			}
+			if strings.TrimSpace(d.Value) == "" && k.Type == "" {
+				continue // such as the graphics data of yEd
+			}
+			v, err := graphmlValue(k, d.Value)
+			if err != nil {
+				return nil, err
+			}
+			m[k.Name] = v
+		}
+		return m, nil
+	}
+
+	directed := gr.EdgeDefault != "undirected"
+	g := NewGraph[string, float64]()
+	for _, gn := range gr.Nodes {
+		if len(gn.Graphs) > 0 {
+			return nil, false, fmt.Errorf("%w: nested graph in node %s", ErrUnsupportedConstruct, gn.ID)
+		}
+		if len(gn.Ports) > 0 {
+			return nil, false, fmt.Errorf("%w: port in node %s", ErrUnsupportedConstruct, gn.ID)
+		}
+		m, err := values("node", gn.Data)
+		if err != nil {
+			return nil, false, err
+		}
+		g.AddNode(nodeFromValues(gn.ID, m))
+	}
+	for _, ge := range gr.Edges {
+		if len(ge.Graphs) > 0 {
+			return nil, false, fmt.Errorf("%w: nested graph in edge %s-%s", ErrUnsupportedConstruct, ge.Source, ge.Target)
+		}
+		m, err := values("edge", ge.Data)
+		if err != nil {
+			return nil, false, err
+		}
+		e := edgeFromValues(ge.Source, ge.Target, m)
+		g.AddEdge(e)
+		if ge.Directed == "false" || (ge.Directed == "" && !directed) {
+			g.AddEdge(e.Reversed())
+		}
+	}
+	return g, directed, nil
+}
+
+func graphmlValue(k graphmlKey, s string) (interface{}, error) {
+	s = strings.TrimSpace(s)
+	var v interface{}
+	var err error
+	switch k.Type {
+	case "boolean":
+		v, err = strconv.ParseBool(s)
+	case "int", "long":
+		v, err = strconv.ParseInt(s, 10, 64)
+	case "float", "double":
+		v, err = strconv.ParseFloat(s, 64)
+	default:
+		v = s
+	}
+	if err != nil {
+		return nil, fmt.Errorf("data %s: %w", k.Name, err)
+	}
+	return v, nil
+}
+
+// nodeFromValues returns node id with the label, position and attributes in m.
+func nodeFromValues[K comparable](id K, m map[string]interface{}) Node[K] {
+	n := NewNode(id)
+	x, okx := toFloat(m["x"])
+	y, oky := toFloat(m["y"])
+	if okx && oky {
+		n.Pos = Position{X: x, Y: y}
+		n.HasPos = true
+		n.Pos.Z, _ = toFloat(m["z"])
+	}
+	for k, v := range m {
+		switch k {
+		case "x", "y", "z":
+		case "label":
+			n.Label = fmt.Sprint(v)
+		default:
+			n.Attrs = n.Attrs.With(k, v)
+		}
+	}
+	return n
+}
+
+// edgeFromValues returns edge from-to with the cost, label and attributes in m.
+func edgeFromValues[K comparable](from, to K, m map[string]interface{}) Edge[K, float64] {
+	e := NewEdge(from, to, 1.0)
+	for k, v := range m {
+		switch k {
+		case "cost", "weight":
+			if c, ok := toFloat(v); ok {
+				e.Cost = c
+			}
+		case "label":
+			e.Label = fmt.Sprint(v)
+		default:
+			e.Attrs = e.Attrs.With(k, v)
+		}
+	}
+	return e
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// WriteGraphML writes g to w as a GraphML document.
+// If directed is false, g must have every edge in both ways, and only the
+// first way is written. Node IDs are written by fmt.Sprint.
+func WriteGraphML[K comparable, W Weight](w io.Writer, g *Graph[K, W], directed bool) error {
+	bw := bufio.NewWriter(w)
+
+	nodeKeys := attrKeyTypes(g.Nodes(), func(n Node[K]) Attrs { return n.Attrs })
+	var edges []Edge[K, W]
+	g.walkEdges(!directed, func(e Edge[K, W]) {
+		edges = append(edges, e)
+	})
+	edgeKeys := attrKeyTypes(edges, func(e Edge[K, W]) Attrs { return e.Attrs })
+
+	bw.WriteString(xml.Header)
+	bw.WriteString("<graphml xmlns=\"http://graphml.graphdrawing.org/xmlns\">\n")
+	writeKey := func(id, kind, name, typ string) {
+		fmt.Fprintf(bw, "  <key id=\"%s\" for=\"%s\" attr.name=\"%s\" attr.type=\"%s\"/>\n",
+			xmlEscape(id), kind, xmlEscape(name), typ)
+	}
+	writeKey("n_label", "node", "label", "string")
+	writeKey("n_x", "node", "x", "double")
+	writeKey("n_y", "node", "y", "double")
+	writeKey("n_z", "node", "z", "double")
+	for _, k := range nodeKeys {
+		writeKey("n_"+k.name, "node", k.name, k.typ)
+	}
+	writeKey("e_label", "edge", "label", "string")
+	writeKey("e_cost", "edge", "cost", "double")
+	for _, k := range edgeKeys {
+		writeKey("e_"+k.name, "edge", k.name, k.typ)
+	}
+
+	edgeDefault := "directed"
+	if !directed {
+		edgeDefault = "undirected"
+	}
+	fmt.Fprintf(bw, "  <graph id=\"G\" edgedefault=\"%s\">\n", edgeDefault)
+	writeData := func(key string, v interface{}) {
+		fmt.Fprintf(bw, "      <data key=\"%s\">%s</data>\n", xmlEscape(key), xmlEscape(formatValue(v)))
+	}
+	g.walkNodes(func(_ int, n Node[K]) {
+		fmt.Fprintf(bw, "    <node id=\"%s\">\n", xmlEscape(fmt.Sprint(n.ID)))
+		if n.Label != "" {
+			writeData("n_label", n.Label)
+		}
+		if n.HasPos {
+			writeData("n_x", n.Pos.X)
+			writeData("n_y", n.Pos.Y)
+			if n.Pos.Z != 0 {
+				writeData("n_z", n.Pos.Z)
+			}
+		}
+		for _, k := range nodeKeys {
+			if v, ok := n.Attrs[k.name]; ok {
+				writeData("n_"+k.name, v)
+			}
+		}
+		bw.WriteString("    </node>\n")
+	})
+	for _, e := range edges {
+		fmt.Fprintf(bw, "    <edge source=\"%s\" target=\"%s\">\n",
+			xmlEscape(fmt.Sprint(e.From)), xmlEscape(fmt.Sprint(e.To)))
+		writeData("e_cost", e.Cost)
+		if e.Label != "" {
+			writeData("e_label", e.Label)
+		}
+		for _, k := range edgeKeys {
+			if v, ok := e.Attrs[k.name]; ok {
+				writeData("e_"+k.name, v)
+			}
+		}
+		bw.WriteString("    </edge>\n")
+	}
+	bw.WriteString("  </graph>\n</graphml>\n")
+
+	return bw.Flush()
+}
+
+type attrKeyType struct {
+	name string
+	typ  string // GraphML attr.type
+}
+
+// reservedAttrs are the names which are mapped onto Node and Edge fields.
+var reservedAttrs = map[string]bool{"label": true, "x": true, "y": true, "z": true, "cost": true, "weight": true}
+
+// attrKeyTypes returns the attribute names of items sorted, with the
+// GraphML type of their values. Names whose values differ in type are string.
+func attrKeyTypes[T any](items []T, attrs func(T) Attrs) []attrKeyType {
+	types := make(map[string]string)
+	for _, it := range items {
+		for k, v := range attrs(it) {
+			if reservedAttrs[k] {
+				continue
+			}
+			typ := graphmlType(v)
+			if old, ok := types[k]; ok && old != typ {
+				typ = "string"
+			}
+			types[k] = typ
+		}
+	}
+
+	keys := make([]attrKeyType, 0, len(types))
+	for k, typ := range types {
+		keys = append(keys, attrKeyType{name: k, typ: typ})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].name < keys[j].name })
+	return keys
+}
+
+func graphmlType(v interface{}) string {
+	switch v.(type) {
+	case bool:
+		return "boolean"
+	case int, int8, int16, int32, int64:
+		return "long"
+	case float32, float64:
+		return "double"
+	}
+	return "string"
+}
+
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	}
+	return fmt.Sprint(v)
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,114 @@
+package graphalgo
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testGraphML = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="node" attr.name="label" attr.type="string"/>
+  <key id="d1" for="node" attr.name="x" attr.type="double"/>
+  <key id="d2" for="node" attr.name="y" attr.type="double"/>
+  <key id="d3" for="node" attr.name="terrain" attr.type="string"><default>grass</default></key>
+  <key id="d4" for="edge" attr.name="weight" attr.type="double"/>
+  <key id="d5" for="edge" attr.name="lanes" attr.type="int"/>
+  <key id="d6" for="edge" attr.name="toll" attr.type="boolean"/>
+  <graph id="G" edgedefault="undirected">
+    <node id="a"><data key="d0">Start</data><data key="d1">1</data><data key="d2">2.5</data></node>
+    <node id="b"><data key="d3">mud</data></node>
+    <node id="c"/>
+    <edge source="a" target="b"><data key="d4">2.5</data><data key="d5">3</data></edge>
+    <edge source="b" target="c" directed="true"><data key="d6">true</data></edge>
+  </graph>
+</graphml>`
+
+func TestReadGraphML(t *testing.T) {
+	g, directed, err := ReadGraphML(strings.NewReader(testGraphML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if directed {
+		t.Error("directed got true, want false")
+	}
+	if g.NumNodes() != 3 {
+		t.Fatalf("NumNodes got %d, want 3", g.NumNodes())
+	}
+
+	a, _ := g.NodeByID("a")
+	if a.Label != "Start" || !a.HasPos || a.Pos != (Position{X: 1, Y: 2.5}) {
+		t.Errorf("node a got %+v", a)
+	}
+	if v, _ := Attr[string](a.Attrs, "terrain"); v != "grass" {
+		t.Errorf("default terrain got %q, want grass", v)
+	}
+	b, _ := g.NodeByID("b")
+	if v, _ := Attr[string](b.Attrs, "terrain"); v != "mud" {
+		t.Errorf("terrain got %q, want mud", v)
+	}
+
+	ab := g.EdgesFrom("a")
+	if len(ab) != 1 || ab[0].Cost != 2.5 {
+		t.Fatalf("edges from a got %v", ab)
+	}
+	if v, _ := Attr[int64](ab[0].Attrs, "lanes"); v != 3 {
+		t.Errorf("lanes got %d, want 3", v)
+	}
+	if len(g.EdgesFrom("c")) != 0 {
+		t.Errorf("directed edge b-c is added both ways")
+	}
+	bc := g.EdgesFrom("b")
+	if len(bc) != 2 || bc[1].Cost != 1 {
+		t.Fatalf("edges from b got %v", bc)
+	}
+	if v, _ := Attr[bool](bc[1].Attrs, "toll"); !v {
+		t.Error("toll got false, want true")
+	}
+}
+
+func TestGraphMLRoundTrip(t *testing.T) {
+	g, _, err := ReadGraphML(strings.NewReader(testGraphML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteGraphML(&buf, g, true); err != nil {
+		t.Fatal(err)
+	}
+	g2, directed, err := ReadGraphML(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !directed {
+		t.Error("directed got false, want true")
+	}
+
+	for _, n := range g.Nodes() {
+		n2, ok := g2.NodeByID(n.ID)
+		if !ok || n2.Label != n.Label || n2.Pos != n.Pos || len(n2.Attrs) != len(n.Attrs) {
+			t.Errorf("node %s got %+v, want %+v", n.ID, n2, n)
+		}
+		if len(g2.EdgesFrom(n.ID)) != len(g.EdgesFrom(n.ID)) {
+			t.Errorf("edges from %s got %v, want %v", n.ID, g2.EdgesFrom(n.ID), g.EdgesFrom(n.ID))
+		}
+	}
+	if v, _ := Attr[bool](g2.EdgesFrom("b")[1].Attrs, "toll"); !v {
+		t.Error("toll is lost")
+	}
+}
+
+func TestReadGraphMLUnsupported(t *testing.T) {
+	for _, doc := range []string{
+		`<graphml><graph edgedefault="directed"><node id="a"/><hyperedge><endpoint node="a"/></hyperedge></graph></graphml>`,
+		`<graphml><graph edgedefault="directed"><node id="a"><graph id="a:"/></node></graph></graphml>`,
+		`<graphml><graph edgedefault="directed"><node id="a"><port name="p"/></node></graph></graphml>`,
+		`<graphml><graph/><graph/></graphml>`,
+	} {
+		if _, _, err := ReadGraphML(strings.NewReader(doc)); !errors.Is(err, ErrUnsupportedConstruct) {
+			t.Errorf("%s got %v, want %v", doc, err, ErrUnsupportedConstruct)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package graphalgo
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Grid is a 2D tile map of Width*Height cells. Every cell has the cost of
+// walking through it, cells whose cost is 0 or less, or +Inf, can not be
+// walked. The cell at column x and row y becomes node y*Width+x, at
+// position (x, y).
+type Grid struct {
+	Width  int
+	Height int
+	costs  []float64 // row-major
+}
+
+// Tiles maps the characters of an ASCII map to cell costs.
+type Tiles map[byte]float64
+
+// DefaultTiles are the tiles of ParseGrid when none are given.
+// '.', ' ' and 'G' cost 1, 'S' (swamp) costs 2, others can not be walked.
+var DefaultTiles = Tiles{'.': 1, ' ': 1, 'G': 1, 'S': 2}
+
+// CornerRule tells whether a diagonal move may cut the corner of a
+// blocked cell next to it.
+type CornerRule int
+
+const (
+	// CutNever allows a diagonal move only if both cells beside it can be walked.
+	CutNever CornerRule = iota
+	// CutOne allows a diagonal move if one of the cells beside it can be walked.
+	CutOne
+	// CutAlways allows every diagonal move between two walkable cells.
+	CutAlways
+)
+
+// Connectivity tells which cells next to a cell can be walked to.
+type Connectivity int
+
+const (
+	// Connect8 allows moves to the 8 cells around, the diagonal ones by CornerRule.
+	Connect8 Connectivity = 8
+	// Connect4 allows moves to the 4 cells which share a side only.
+	Connect4 Connectivity = 4
+)
+
+// GridOptions tells Grid.Graph how cells are connected.
+// The zero Connectivity means Connect8.
+type GridOptions struct {
+	Connectivity Connectivity
+	Corners      CornerRule
+}
+
+// NewGrid returns a width*height grid whose cells all cost 1.
+func NewGrid(width, height int) *Grid {
+	gr := &Grid{Width: width, Height: height, costs: make([]float64, width*height)}
+	for i := range gr.costs {
+		gr.costs[i] = 1
+	}
+	return gr
+}
+
+// ParseGrid returns the grid drawn by s, one line per row. Every line must
+// have the same length, and the grid must have a cell at least. tiles gives the cost of each character, nil means
+// DefaultTiles, characters not in it can not be walked.
+func ParseGrid(s string, tiles Tiles) (*Grid, error) {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	rows := make([][]byte, len(lines))
+	for i, l := range lines {
+		rows[i] = []byte(strings.TrimSuffix(l, "\r"))
+	}
+	return GridFromBytes(rows, tiles)
+}
+
+// GridFromBytes is ParseGrid with the rows already split.
+func GridFromBytes(rows [][]byte, tiles Tiles) (*Grid, error) {
+	if tiles == nil {
+		tiles = DefaultTiles
+	}
+	gr, err := newGridOf(len(rows), func(y int) int { return len(rows[y]) })
+	if err != nil {
+		return nil, err
+	}
+	for y, row := range rows {
+		for x, c := range row {
+			gr.costs[y*gr.Width+x] = tiles[c]
+		}
+	}
+	return gr, nil
+}
+
+// GridFromCosts returns the grid whose cell at column x and row y costs costs[y][x].
+func GridFromCosts(costs [][]float64) (*Grid, error) {
+	gr, err := newGridOf(len(costs), func(y int) int { return len(costs[y]) })
+	if err != nil {
+		return nil, err
+	}
+	for y, row := range costs {
+		copy(gr.costs[y*gr.Width:], row)
+	}
+	return gr, nil
+}
+
+func newGridOf(height int, width func(y int) int) (*Grid, error) {
+	if height == 0 || width(0) == 0 {
+		return nil, fmt.Errorf("%w: empty grid", ErrMapFormat)
+	}
+	w := width(0)
+	for y := 1; y < height; y++ {
+		if width(y) != w {
+			return nil, fmt.Errorf("%w: grid row %d has %d cells, want %d", ErrMapFormat, y, width(y), w)
+		}
+	}
+	return &Grid{Width: w, Height: height, costs: make([]float64, w*height)}, nil
+}
+
+// ID returns the node ID of the cell at (x, y).
+func (gr *Grid) ID(x, y int) int {
+	return y*gr.Width + x
+}
+
+// XY returns the column and row of node id.
+// An empty grid has no cell, it returns -1, -1.
+func (gr *Grid) XY(id int) (int, int) {
+	if gr.Width == 0 {
+		return -1, -1
+	}
+	return id % gr.Width, id / gr.Width
+}
+
+// In returns true if (x, y) is in the grid.
+func (gr *Grid) In(x, y int) bool {
+	return x >= 0 && y >= 0 && x < gr.Width && y < gr.Height
+}
+
+// passableID returns true if node id is a walkable cell.
+func (gr *Grid) passableID(id int) bool {
+	if id < 0 || id >= gr.Width*gr.Height {
+		return false
+	}
+	return gr.Passable(gr.XY(id))
+}
+
+// Cost returns the cost of the cell at (x, y).
+func (gr *Grid) Cost(x, y int) float64 {
+	return gr.costs[gr.ID(x, y)]
+}
+
+// SetCost sets the cost of the cell at (x, y).
+func (gr *Grid) SetCost(x, y int, cost float64) {
+	gr.costs[gr.ID(x, y)] = cost
+}
+
+// Passable returns true if (x, y) is in the grid and can be walked.
+func (gr *Grid) Passable(x, y int) bool {
+	if !gr.In(x, y) {
+		return false
+	}
+	c := gr.Cost(x, y)
+	return c > 0 && !math.IsInf(c, 1)
+}
+
+// Graph builds the graph of the grid. Every walkable cell is a node, in
+// row-major order. Moving between two cells costs the mean of their costs,
+// times sqrt(2) for diagonal moves. So with cells which cost at least 1,
+// Octile is admissible on the graph of 8 connectivity, and Manhattan on the
+// graph of 4 connectivity.
+func (gr *Grid) Graph(opts GridOptions) *Graph[int, float64] {
+	return gr.graphIn(0, 0, gr.Width, gr.Height, opts)
+}
+
+// graphIn builds the graph of the cells from (x0, y0) to (x1, y1), x1 and
+// y1 excluded. Edges to cells out of it are left out.
+func (gr *Grid) graphIn(x0, y0, x1, y1 int, opts GridOptions) *Graph[int, float64] {
+	g := NewGraph[int, float64]()
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			if gr.Passable(x, y) {
+				g.AddNode(NewNodeAt(gr.ID(x, y), float64(x), float64(y)))
+			}
+		}
+	}
+
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			if !gr.Passable(x, y) {
+				continue
+			}
+			gr.neighbors(x, y, opts, func(tx, ty int, dist float64) {
+				if tx < x0 || ty < y0 || tx >= x1 || ty >= y1 {
+					return
+				}
+				cost := (gr.Cost(x, y) + gr.Cost(tx, ty)) / 2 * dist
+				g.AddEdge(NewEdge(gr.ID(x, y), gr.ID(tx, ty), cost))
+			})
+		}
+	}
+	return g
+}
+
+// neighbors calls fn with every cell which can be walked to from (x, y),
+// and the distance to it.
+func (gr *Grid) neighbors(x, y int, opts GridOptions, fn func(tx, ty int, dist float64)) {
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if dx == 0 && dy == 0 {
+				continue
+			}
+			tx, ty := x+dx, y+dy
+			if !gr.Passable(tx, ty) {
+				continue
+			}
+			if dx == 0 || dy == 0 {
+				fn(tx, ty, 1)
+				continue
+			}
+			if opts.Connectivity == Connect4 || !gr.canCut(x, y, dx, dy, opts.Corners) {
+				continue
+			}
+			fn(tx, ty, math.Sqrt2)
+		}
+	}
+}
+
+// canCut returns true if the diagonal move from (x, y) by (dx, dy) keeps rule.
+func (gr *Grid) canCut(x, y, dx, dy int, rule CornerRule) bool {
+	a, b := gr.Passable(x+dx, y), gr.Passable(x, y+dy)
+	switch rule {
+	case CutOne:
+		return a || b
+	case CutAlways:
+		return true
+	}
+	return a && b
+}
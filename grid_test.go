@@ -0,0 +1,85 @@
+package graphalgo
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGridGraph(t *testing.T) {
+	gr, err := ParseGrid(""+
+		"..#\n"+
+		".#.\n"+
+		"...\n", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gr.Width != 3 || gr.Height != 3 || gr.Passable(2, 0) || !gr.Passable(2, 1) {
+		t.Fatalf("grid got %+v", gr)
+	}
+
+	cases := []struct {
+		opts  GridOptions
+		edges int // from cell (0, 1)
+		cost  float64
+	}{
+		{GridOptions{Connectivity: Connect4}, 2, 6},
+		{GridOptions{}, 2, 6},
+		{GridOptions{Corners: CutOne}, 4, 3 * math.Sqrt2},
+		{GridOptions{Corners: CutAlways}, 4, math.Sqrt2},
+	}
+	for _, c := range cases {
+		g := gr.Graph(c.opts)
+		if g.NumNodes() != 7 {
+			t.Errorf("%+v NumNodes got %d, want 7", c.opts, g.NumNodes())
+		}
+		if n := len(g.EdgesFrom(gr.ID(0, 1))); n != c.edges {
+			t.Errorf("%+v edges from (0, 1) got %d, want %d", c.opts, n, c.edges)
+		}
+
+		d := NewAstarWithH(g, gr.ID(1, 0), gr.ID(2, 1), Octile(g))
+		d.Search()
+		p, err := d.PathToTarget()
+		if err != nil || math.Abs(p.Cost-c.cost) > 1e-9 {
+			t.Errorf("%+v path got %v %v, want cost %v", c.opts, p, err, c.cost)
+		}
+	}
+}
+
+func TestGridCosts(t *testing.T) {
+	gr, err := GridFromCosts([][]float64{
+		{1, 3},
+		{math.Inf(1), 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := gr.Graph(GridOptions{Corners: CutAlways})
+	if g.NumNodes() != 3 {
+		t.Fatalf("NumNodes got %d, want 3", g.NumNodes())
+	}
+	e := g.EdgesFrom(gr.ID(0, 0))
+	if len(e) != 2 || e[0].Cost != 2 || math.Abs(e[1].Cost-math.Sqrt2) > 1e-9 {
+		t.Errorf("edges from (0, 0) got %v", e)
+	}
+	if x, y := gr.XY(3); x != 1 || y != 1 {
+		t.Errorf("XY(3) got %d, %d", x, y)
+	}
+
+	if _, err := ParseGrid("..\n.\n", nil); !errors.Is(err, ErrMapFormat) {
+		t.Errorf("ragged grid got %v, want %v", err, ErrMapFormat)
+	}
+	if _, err := ParseGrid("", nil); !errors.Is(err, ErrMapFormat) {
+		t.Errorf("empty grid got %v, want %v", err, ErrMapFormat)
+	}
+	if _, err := GridFromBytes([][]byte{{}, {}}, nil); !errors.Is(err, ErrMapFormat) {
+		t.Errorf("grid of empty rows got %v, want %v", err, ErrMapFormat)
+	}
+	if _, err := ReadMovingAIMap(strings.NewReader("type octile\nheight 0\nwidth 0\nmap\n")); !errors.Is(err, ErrMapFormat) {
+		t.Errorf("movingai map of width 0 got %v, want %v", err, ErrMapFormat)
+	}
+	if x, y := NewGrid(0, 0).XY(3); x != -1 || y != -1 {
+		t.Errorf("XY(3) of an empty grid got %d, %d", x, y)
+	}
+}
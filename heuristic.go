@@ -0,0 +1,122 @@
+package graphalgo
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// EarthRadiusMeters is the mean radius of the earth, for Haversine.
+const EarthRadiusMeters = 6371008.8
+
+// Heuristics estimate the cost from nd1 to nd2 by the positions of the nodes.
+// They are given to NewAstarWithH. A heuristic is admissible if it never
+// overestimates, and consistent if it also never drops by more than the
+// cost of an edge, then Astar finds the shortest path, see
+// Astar.SetAdmissible. The distances below are consistent wherever they are
+// admissible, as they keep the triangle inequality. The geometric ones
+// below are admissible only if every edge costs at least the distance they
+// measure between its two nodes. A node without position is estimated as 0,
+// which is always admissible. The result is converted to W, integer weights
+// are truncated toward zero, which keeps admissibility.
+
+// Zero returns the heuristic which is always 0. Astar with it is Dijkstra.
+// Admissible.
+func Zero[K comparable, W Weight]() func(nd1, nd2 K) W {
+	return func(nd1, nd2 K) W { return 0 }
+}
+
+// Euclidean returns the straight-line distance in 2D or 3D.
+// Admissible on any graph whose edges cost at least their length.
+func Euclidean[K comparable, W Weight](g *Graph[K, W]) func(nd1, nd2 K) W {
+	return positionHeuristic(g, func(a, b Position) float64 {
+		dx, dy, dz := a.X-b.X, a.Y-b.Y, a.Z-b.Z
+		return math.Sqrt(dx*dx + dy*dy + dz*dz)
+	})
+}
+
+// Manhattan returns |dx|+|dy|+|dz|.
+// Admissible on 4-connected grids with unit cost, overestimates on
+// 8-connected grids and on graphs with diagonal edges.
+func Manhattan[K comparable, W Weight](g *Graph[K, W]) func(nd1, nd2 K) W {
+	return positionHeuristic(g, func(a, b Position) float64 {
+		return math.Abs(a.X-b.X) + math.Abs(a.Y-b.Y) + math.Abs(a.Z-b.Z)
+	})
+}
+
+// Chebyshev returns max(|dx|, |dy|, |dz|).
+// Admissible on 8-connected grids whose diagonal moves cost 1 like
+// straight ones, and on any grid which Octile or Manhattan fits.
+func Chebyshev[K comparable, W Weight](g *Graph[K, W]) func(nd1, nd2 K) W {
+	return positionHeuristic(g, func(a, b Position) float64 {
+		return math.Max(math.Abs(a.X-b.X), math.Max(math.Abs(a.Y-b.Y), math.Abs(a.Z-b.Z)))
+	})
+}
+
+// Octile returns the 2D distance on an 8-connected grid whose straight moves
+// cost 1 and diagonal moves cost sqrt(2). Z is ignored.
+// Admissible on such grids, it is also the exact cost when no cell blocks.
+func Octile[K comparable, W Weight](g *Graph[K, W]) func(nd1, nd2 K) W {
+	return positionHeuristic(g, func(a, b Position) float64 {
+		dx, dy := math.Abs(a.X-b.X), math.Abs(a.Y-b.Y)
+		return dx + dy + (math.Sqrt2-2)*math.Min(dx, dy)
+	})
+}
+
+// Haversine returns the great-circle distance on a sphere of radius r.
+// X is longitude and Y is latitude, both in degrees. Z is ignored.
+// Use EarthRadiusMeters as r for road networks whose costs are meters.
+// Admissible if every edge costs at least the great-circle distance of it.
+func Haversine[K comparable, W Weight](g *Graph[K, W], r float64) func(nd1, nd2 K) W {
+	return positionHeuristic(g, func(a, b Position) float64 {
+		lat1, lat2 := a.Y*math.Pi/180, b.Y*math.Pi/180
+		dlat := lat2 - lat1
+		dlon := (b.X - a.X) * math.Pi / 180
+		h := math.Sin(dlat/2)*math.Sin(dlat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dlon/2)*math.Sin(dlon/2)
+		return 2 * r * math.Asin(math.Min(1, math.Sqrt(h)))
+	})
+}
+
+// Weighted returns h multiplied by w, which is also known as inflated heuristic.
+// With w > 1 Astar expands fewer nodes, and the path found costs at most
+// w times the shortest one if h is admissible. Not admissible when w > 1.
+func Weighted[K comparable, W Weight](h func(nd1, nd2 K) W, w float64) func(nd1, nd2 K) W {
+	return func(nd1, nd2 K) W {
+		return W(float64(h(nd1, nd2)) * w)
+	}
+}
+
+// Noisy returns h plus a random noise in [-amplitude, amplitude], never
+// below 0. It is for testing that callers do not rely on a tight heuristic.
+// The noise is repeatable with the same seed when it is called in the same
+// order. It is safe for concurrent use, such as by a parallel BiAstar.
+// Not admissible nor consistent, so it must never be marked so by
+// SetAdmissible or SearchOptions.Admissible.
+func Noisy[K comparable, W Weight](h func(nd1, nd2 K) W, amplitude float64, seed int64) func(nd1, nd2 K) W {
+	rnd := rand.New(rand.NewSource(seed))
+	var mu sync.Mutex
+	return func(nd1, nd2 K) W {
+		mu.Lock()
+		noise := (rnd.Float64()*2 - 1) * amplitude
+		mu.Unlock()
+		v := float64(h(nd1, nd2)) + noise
+		if v < 0 {
+			v = 0
+		}
+		return W(v)
+	}
+}
+
+func positionHeuristic[K comparable, W Weight](g *Graph[K, W], dist func(a, b Position) float64) func(nd1, nd2 K) W {
+	return func(nd1, nd2 K) W {
+		a, ok := g.Position(nd1)
+		if !ok {
+			return 0
+		}
+		b, ok := g.Position(nd2)
+		if !ok {
+			return 0
+		}
+		return W(dist(a, b))
+	}
+}
@@ -0,0 +1,94 @@
+package graphalgo
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestHeuristic(t *testing.T) {
+	g := NewGraph[string, float64]()
+	g.AddNode(NewNodeAt("a", 0, 0))
+	g.AddNode(NewNodeAt("b", 3, 4))
+	g.AddNode(NewNode("nowhere"))
+
+	cases := []struct {
+		name string
+		h    func(nd1, nd2 string) float64
+		want float64
+	}{
+		{"Zero", Zero[string, float64](), 0},
+		{"Euclidean", Euclidean(g), 5},
+		{"Manhattan", Manhattan(g), 7},
+		{"Chebyshev", Chebyshev(g), 4},
+		{"Octile", Octile(g), 4 + 3*math.Sqrt2 - 3},
+		{"Weighted", Weighted(Euclidean(g), 1.5), 7.5},
+	}
+	for _, c := range cases {
+		if v := c.h("a", "b"); math.Abs(v-c.want) > 1e-9 {
+			t.Errorf("%s(a, b) got %v, want %v", c.name, v, c.want)
+		}
+		if v := c.h("a", "nowhere"); v != 0 {
+			t.Errorf("%s(a, nowhere) got %v, want 0", c.name, v)
+		}
+	}
+
+	noisy := Noisy(Euclidean(g), 1, 7)
+	for i := 0; i < 100; i++ {
+		if v := noisy("a", "b"); v < 4 || v > 6 {
+			t.Errorf("Noisy(a, b) got %v, want in [4, 6]", v)
+		}
+	}
+
+	// a parallel BiAstar calls it from two goroutines, see go test -race
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				noisy("a", "b")
+			}
+		}()
+	}
+	wg.Wait()
+
+	geo := NewGraph[string, int]()
+	geo.AddNode(NewNodeAt("equator", 0, 0))
+	geo.AddNode(NewNodeAt("north", 0, 1))
+	if v := Haversine(geo, EarthRadiusMeters)("equator", "north"); v != 111195 {
+		t.Errorf("Haversine got %v, want %v", v, 111195)
+	}
+}
+
+// TestAstarHeuristic tests that Astar with admissible heuristics finds the
+// shortest path with fewer expansions than Dijkstra.
+func TestAstarHeuristic(t *testing.T) {
+	g := newTestGrid(20)
+	source, target := 0, 20*20-1-5
+
+	d := NewDijkstra(g, source, target)
+	d.Search()
+	want, err := d.PathToTarget()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, h := range map[string]func(nd1, nd2 int) float64{
+		"Euclidean": Euclidean(g),
+		"Octile":    Octile(g),
+	} {
+		s, _ := NewSearcher(AlgorithmAstar, g, source, target, SearchOptions[int, float64]{
+			Heuristic:  h,
+			Admissible: true,
+		})
+		s.Search()
+		p, err := s.PathToTarget()
+		if err != nil || math.Abs(p.Cost-want.Cost) > 1e-9 || !p.Optimal {
+			t.Errorf("Astar with %s got %v %v, want cost %v", name, p, err, want.Cost)
+		}
+		if s.Stats().Expanded >= d.Stats().Expanded {
+			t.Errorf("Astar with %s expanded %d, Dijkstra %d", name, s.Stats().Expanded, d.Stats().Expanded)
+		}
+	}
+}
@@ -0,0 +1,444 @@
+package graphalgo
+
+import "math"
+
+// AlgorithmHPA names the paths found by HPA.
+const AlgorithmHPA Algorithm = "hpa"
+
+// hpaMaxEntrance is the widest entrance which gets one transition in the
+// middle, wider ones get one at each end.
+const hpaMaxEntrance = 6
+
+// Node IDs of the start and the goal of a query in the abstract graph,
+// cell IDs are never negative.
+const (
+	hpaStart = -1
+	hpaGoal  = -2
+)
+
+// HPA is hierarchical path-finding A* (HPA*) on a grid.
+// The grid is cut into square clusters. Where two clusters touch, each
+// walkable run of the border gets one or two transitions, a pair of cells
+// facing each other, which are the entrances of the clusters. If the
+// corner rule lets diagonal moves cut corners, a diagonal move between two
+// clusters which meet at a corner is a transition too, and so is, with
+// CutAlways, one across a border between two blocked cells. The abstract
+// graph links the entrances of a cluster by their shortest paths in it,
+// found by Dijkstra, and the two cells of a transition by the move across.
+// A query links its start and goal to the entrances of their clusters,
+// searches the abstract graph by Astar, then refines every abstract edge
+// into cells. Paths are near-optimal, not optimal.
+// An HPA is not safe for concurrent use, FindPath changes the abstract graph.
+type HPA struct {
+	grid   *Grid
+	opts   GridOptions
+	size   int // cells per side of a cluster
+	cw, ch int // clusters per row and column
+
+	clusters  []*Graph[int, float64] // the cells of each cluster
+	entrances [][]int                // entrance cells of each cluster
+	borders   map[hpaBorder][][2]int // transitions, the first cell is in the cluster of the border
+	abstract  *Graph[int, float64]
+
+	startLinks []int // entrances linked from hpaStart by the last query
+	goalLinks  []int // entrances linked to hpaGoal by the last query
+}
+
+// hpaBorder is where a cluster touches the cluster on side of it.
+type hpaBorder struct {
+	cluster int
+	side    hpaSide
+}
+
+// hpaSide is the side of a cluster where it touches another cluster.
+type hpaSide int
+
+const (
+	hpaRight     hpaSide = iota // the next cluster on the right
+	hpaDown                     // the cluster below
+	hpaDownRight                // the cluster below on the right, at the corner
+	hpaDownLeft                 // the cluster below on the left, at the corner
+)
+
+// NewHPA builds the abstract graph of gr with clusters of size*size cells.
+// The cells of gr must be changed by HPA.SetCost afterwards.
+func NewHPA(gr *Grid, opts GridOptions, size int) *HPA {
+	if size < 2 {
+		size = 2
+	}
+	h := &HPA{
+		grid:     gr,
+		opts:     opts,
+		size:     size,
+		cw:       (gr.Width + size - 1) / size,
+		ch:       (gr.Height + size - 1) / size,
+		borders:  make(map[hpaBorder][][2]int),
+		abstract: NewGraph[int, float64](),
+	}
+	n := h.cw * h.ch
+	h.clusters = make([]*Graph[int, float64], n)
+	h.entrances = make([][]int, n)
+
+	h.abstract.AddNode(NewNode(hpaStart))
+	h.abstract.AddNode(NewNode(hpaGoal))
+	for c := 0; c < n; c++ {
+		for side := hpaRight; side <= hpaDownLeft; side++ {
+			h.buildBorder(hpaBorder{c, side})
+		}
+	}
+	for c := 0; c < n; c++ {
+		h.buildCluster(c)
+	}
+	return h
+}
+
+// Abstract returns the abstract graph. Node IDs are cell IDs of the
+// entrances, -1 and -2 are the start and the goal of the last query.
+func (h *HPA) Abstract() *Graph[int, float64] {
+	return h.abstract
+}
+
+// Cluster returns the cluster of cell (x, y).
+func (h *HPA) Cluster(x, y int) int {
+	return (y/h.size)*h.cw + x/h.size
+}
+
+// bounds returns the cells of cluster c, from (x0, y0) to (x1, y1) excluded.
+func (h *HPA) bounds(c int) (x0, y0, x1, y1 int) {
+	x0, y0 = (c%h.cw)*h.size, (c/h.cw)*h.size
+	x1, y1 = x0+h.size, y0+h.size
+	if x1 > h.grid.Width {
+		x1 = h.grid.Width
+	}
+	if y1 > h.grid.Height {
+		y1 = h.grid.Height
+	}
+	return
+}
+
+// SetCost changes the cost of cell (x, y), and rebuilds the clusters
+// whose abstract graph it changes.
+func (h *HPA) SetCost(x, y int, cost float64) {
+	h.grid.SetCost(x, y, cost)
+
+	c := h.Cluster(x, y)
+	x0, y0, x1, y1 := h.bounds(c)
+	rebuild := []int{c}
+	var px, py []int // corners of c which (x, y) is at
+	if x == x1-1 && x1 < h.grid.Width {
+		h.buildBorder(hpaBorder{c, hpaRight})
+		rebuild = append(rebuild, c+1)
+		px = append(px, x1)
+	}
+	if y == y1-1 && y1 < h.grid.Height {
+		h.buildBorder(hpaBorder{c, hpaDown})
+		rebuild = append(rebuild, c+h.cw)
+		py = append(py, y1)
+	}
+	if x == x0 && x0 > 0 {
+		h.buildBorder(hpaBorder{c - 1, hpaRight})
+		rebuild = append(rebuild, c-1)
+		px = append(px, x0)
+	}
+	if y == y0 && y0 > 0 {
+		h.buildBorder(hpaBorder{c - h.cw, hpaDown})
+		rebuild = append(rebuild, c-h.cw)
+		py = append(py, y0)
+	}
+	// the four clusters at a corner share the cells around it
+	for _, cx := range px {
+		for _, cy := range py {
+			tl := h.Cluster(cx-1, cy-1)
+			h.buildBorder(hpaBorder{tl, hpaDownRight})
+			h.buildBorder(hpaBorder{tl + 1, hpaDownLeft})
+			rebuild = append(rebuild, tl, tl+1, tl+h.cw, tl+h.cw+1)
+		}
+	}
+	for _, c := range rebuild {
+		h.buildCluster(c)
+	}
+}
+
+// buildBorder finds the transitions of border b, and links their cells
+// in the abstract graph.
+func (h *HPA) buildBorder(b hpaBorder) {
+	for _, t := range h.borders[b] {
+		h.abstract.RemoveEdge(t[0], t[1])
+		h.abstract.RemoveEdge(t[1], t[0])
+	}
+	delete(h.borders, b)
+
+	var ts [][2]int
+	if b.side == hpaRight || b.side == hpaDown {
+		ts = h.sideTransitions(b)
+	} else {
+		ts = h.cornerTransitions(b)
+	}
+
+	gr := h.grid
+	for _, t := range ts {
+		for _, id := range t {
+			cx, cy := gr.XY(id)
+			h.abstract.AddNode(NewNodeAt(id, float64(cx), float64(cy)))
+		}
+		cost := h.crossCost(t[0], t[1])
+		h.abstract.AddEdge(NewEdge(t[0], t[1], cost))
+		h.abstract.AddEdge(NewEdge(t[1], t[0], cost))
+	}
+	h.borders[b] = ts
+}
+
+// sideTransitions returns the transitions of the border b on the right or
+// below its cluster.
+func (h *HPA) sideTransitions(b hpaBorder) [][2]int {
+	gr := h.grid
+	x0, y0, x1, y1 := h.bounds(b.cluster)
+	// walk the cells (x, y) of the cluster along the border, (x+dx, y+dy) faces them
+	x, y, dx, dy, n := x1-1, y0, 1, 0, y1-y0
+	if b.side == hpaDown {
+		x, y, dx, dy, n = x0, y1-1, 0, 1, x1-x0
+	}
+	if x+dx >= gr.Width || y+dy >= gr.Height {
+		return nil
+	}
+
+	var ts [][2]int
+	run := 0
+	for i := 0; i <= n; i++ {
+		cx, cy := x+dy*i, y+dx*i
+		if i < n && gr.Passable(cx, cy) && gr.Passable(cx+dx, cy+dy) {
+			run++
+			continue
+		}
+		if run > 0 {
+			// the run is from i-run to i-1
+			at := []int{i - run + (run-1)/2}
+			if run >= hpaMaxEntrance {
+				at = []int{i - run, i - 1}
+			}
+			for _, j := range at {
+				ax, ay := x+dy*j, y+dx*j
+				ts = append(ts, [2]int{gr.ID(ax, ay), gr.ID(ax+dx, ay+dy)})
+			}
+		}
+		run = 0
+	}
+
+	// a diagonal move between two blocked cells is the only way across
+	for i := 0; i+1 < n; i++ {
+		for _, j := range [][2]int{{i, i + 1}, {i + 1, i}} {
+			// from cell j[0] of the cluster to the cell facing cell j[1]
+			ax, ay := x+dy*j[0], y+dx*j[0]
+			fx, fy := x+dy*j[1]+dx, y+dx*j[1]+dy
+			if !gr.Passable(ax+dx, ay+dy) && !gr.Passable(fx-dx, fy-dy) && h.canCross(ax, ay, fx, fy) {
+				ts = append(ts, [2]int{gr.ID(ax, ay), gr.ID(fx, fy)})
+			}
+		}
+	}
+	return ts
+}
+
+// cornerTransitions returns the transition of the border b at the bottom
+// corner of its cluster, if the diagonal move across it can be made.
+func (h *HPA) cornerTransitions(b hpaBorder) [][2]int {
+	if h.opts.Corners == CutNever {
+		return nil
+	}
+	x0, _, x1, y1 := h.bounds(b.cluster)
+	ax, ay, fx, fy := x1-1, y1-1, x1, y1
+	if b.side == hpaDownLeft {
+		ax, fx = x0, x0-1
+	}
+	if !h.canCross(ax, ay, fx, fy) {
+		return nil
+	}
+	return [][2]int{{h.grid.ID(ax, ay), h.grid.ID(fx, fy)}}
+}
+
+// canCross returns true if the move from cell (ax, ay) to the cell (bx, by)
+// next to it can be made.
+func (h *HPA) canCross(ax, ay, bx, by int) bool {
+	gr := h.grid
+	if !gr.Passable(ax, ay) || !gr.Passable(bx, by) {
+		return false
+	}
+	if ax == bx || ay == by {
+		return true
+	}
+	return h.opts.Connectivity != Connect4 && gr.canCut(ax, ay, bx-ax, by-ay, h.opts.Corners)
+}
+
+// crossCost returns the cost of the move from cell a to the cell b next to
+// it, as in the graph of the grid.
+func (h *HPA) crossCost(a, b int) float64 {
+	ax, ay := h.grid.XY(a)
+	bx, by := h.grid.XY(b)
+	cost := (h.grid.Cost(ax, ay) + h.grid.Cost(bx, by)) / 2
+	if ax != bx && ay != by {
+		cost *= math.Sqrt2
+	}
+	return cost
+}
+
+// buildCluster rebuilds the graph of cluster c, and links its entrances
+// in the abstract graph by their shortest paths in it.
+func (h *HPA) buildCluster(c int) {
+	for _, a := range h.entrances[c] {
+		for _, b := range h.entrances[c] {
+			h.abstract.RemoveEdge(a, b)
+		}
+	}
+
+	x0, y0, x1, y1 := h.bounds(c)
+	h.clusters[c] = h.grid.graphIn(x0, y0, x1, y1, h.opts)
+
+	// entrances are on the borders of c and of the clusters left and above
+	var entrances []int
+	seen := make(map[int]bool)
+	add := func(id int) {
+		if !seen[id] {
+			seen[id] = true
+			entrances = append(entrances, id)
+		}
+	}
+	for side := hpaRight; side <= hpaDownLeft; side++ {
+		for _, t := range h.borders[hpaBorder{c, side}] {
+			add(t[0])
+		}
+	}
+	var others []hpaBorder
+	if x0 > 0 {
+		others = append(others, hpaBorder{c - 1, hpaRight})
+	}
+	if y0 > 0 {
+		others = append(others, hpaBorder{c - h.cw, hpaDown})
+		if x0 > 0 {
+			others = append(others, hpaBorder{c - h.cw - 1, hpaDownRight})
+		}
+		if x1 < h.grid.Width {
+			others = append(others, hpaBorder{c - h.cw + 1, hpaDownLeft})
+		}
+	}
+	for _, b := range others {
+		for _, t := range h.borders[b] {
+			add(t[1])
+		}
+	}
+	h.entrances[c] = entrances
+
+	for _, a := range entrances {
+		for _, b := range entrances {
+			if a == b {
+				continue
+			}
+			if cost, ok := h.localCost(c, a, b); ok {
+				h.abstract.AddEdge(NewEdge(a, b, cost))
+			}
+		}
+	}
+}
+
+// localCost returns the cost of the shortest path from a to b in cluster c.
+func (h *HPA) localCost(c, a, b int) (float64, bool) {
+	d := NewDijkstra(h.clusters[c], a, b)
+	d.Search()
+	p, err := d.PathToTarget()
+	return p.Cost, err == nil
+}
+
+// localPath returns the shortest path from a to b in cluster c.
+func (h *HPA) localPath(c, a, b int) (Path[int, float64], error) {
+	g := h.clusters[c]
+	d := NewAstarWithH(g, a, b, Octile(g))
+	d.Search()
+	return d.PathToTarget()
+}
+
+// FindPath returns a path from cell s to cell t, see Grid.ID.
+func (h *HPA) FindPath(s, t int) (Path[int, float64], error) {
+	gr := h.grid
+	if s < 0 || t < 0 || s >= gr.Width*gr.Height || t >= gr.Width*gr.Height {
+		return Path[int, float64]{}, ErrInvalidNodeIndex
+	}
+	sx, sy := gr.XY(s)
+	tx, ty := gr.XY(t)
+	if !gr.Passable(sx, sy) || !gr.Passable(tx, ty) {
+		return Path[int, float64]{}, ErrInvalidNodeIndex
+	}
+	cs, ct := h.Cluster(sx, sy), h.Cluster(tx, ty)
+
+	// link the start and the goal to the entrances of their clusters
+	for _, e := range h.startLinks {
+		h.abstract.RemoveEdge(hpaStart, e)
+	}
+	for _, e := range h.goalLinks {
+		h.abstract.RemoveEdge(e, hpaGoal)
+	}
+	h.startLinks, h.goalLinks = nil, nil
+	h.abstract.SetPosition(hpaStart, Position{X: float64(sx), Y: float64(sy)})
+	h.abstract.SetPosition(hpaGoal, Position{X: float64(tx), Y: float64(ty)})
+	for _, e := range h.entrances[cs] {
+		if cost, ok := h.localCost(cs, s, e); ok {
+			h.abstract.AddEdge(NewEdge(hpaStart, e, cost))
+			h.startLinks = append(h.startLinks, e)
+		}
+	}
+	for _, e := range h.entrances[ct] {
+		if cost, ok := h.localCost(ct, e, t); ok {
+			h.abstract.AddEdge(NewEdge(e, hpaGoal, cost))
+			h.goalLinks = append(h.goalLinks, e)
+		}
+	}
+
+	best := Path[int, float64]{Cost: math.Inf(1)}
+	if cs == ct {
+		if p, err := h.localPath(cs, s, t); err == nil {
+			best = p
+		}
+	}
+
+	d := NewAstarWithH(h.abstract, hpaStart, hpaGoal, Octile(h.abstract))
+	d.Search()
+	if ap, err := d.PathToTarget(); err == nil && ap.Cost < best.Cost {
+		if p, err := h.refine(ap, s, t); err == nil {
+			best = p
+		}
+	}
+
+	if math.IsInf(best.Cost, 1) {
+		return Path[int, float64]{}, ErrPathNotFound
+	}
+	p := NewPath(s, best.Edges, AlgorithmHPA, false)
+	return p, nil
+}
+
+// refine turns the abstract path ap into cells.
+func (h *HPA) refine(ap Path[int, float64], s, t int) (Path[int, float64], error) {
+	var edges []Edge[int, float64]
+	for _, e := range ap.Edges {
+		from, to := e.From, e.To
+		if from == hpaStart {
+			from = s
+		}
+		if to == hpaGoal {
+			to = t
+		}
+		if from == to {
+			continue
+		}
+
+		fx, fy := h.grid.XY(from)
+		tx, ty := h.grid.XY(to)
+		c := h.Cluster(fx, fy)
+		if c != h.Cluster(tx, ty) {
+			edges = append(edges, NewEdge(from, to, e.Cost)) // across a border
+			continue
+		}
+		p, err := h.localPath(c, from, to)
+		if err != nil {
+			return Path[int, float64]{}, err
+		}
+		edges = append(edges, p.Edges...)
+	}
+	return NewPath(s, edges, AlgorithmHPA, false), nil
+}
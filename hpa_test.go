@@ -0,0 +1,106 @@
+package graphalgo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestHPA(t *testing.T) {
+	testHPA(t, MovingAIOptions, 0.2)
+}
+
+func TestHPACutCorners(t *testing.T) {
+	testHPA(t, GridOptions{Corners: CutOne}, 0.3)
+	testHPA(t, GridOptions{Corners: CutAlways}, 0.4)
+
+	// the clusters are linked only by a diagonal move cutting two corners
+	for _, s := range []string{
+		"..##\n..##\n##..\n##..\n", // at the corner of the clusters
+		"..#.\n.#..\n",             // across their border
+	} {
+		gr, _ := ParseGrid(s, nil)
+		src, dst := gr.ID(0, 0), gr.ID(gr.Width-1, gr.Height-1)
+		for _, corners := range []CornerRule{CutNever, CutOne, CutAlways} {
+			h := NewHPA(gr, GridOptions{Corners: corners}, 2)
+			var want error = ErrPathNotFound
+			if corners == CutAlways {
+				want = nil
+			}
+			if _, err := h.FindPath(src, dst); err != want {
+				t.Errorf("rule %d on\n%s got %v, want %v", corners, s, err, want)
+			}
+		}
+	}
+}
+
+// testHPA checks that HPA finds a path on random grids whenever Astar does,
+// and that it walks the edges of the grid graph.
+func testHPA(t *testing.T, opts GridOptions, blocked float64) {
+	t.Helper()
+	rnd := rand.New(rand.NewSource(2))
+	for round := 0; round < 10; round++ {
+		gr := newTestRandomGrid(rnd, 37, 29, blocked)
+		g := gr.Graph(opts)
+		h := NewHPA(gr, opts, 8)
+
+		nodes := g.Nodes()
+		for q := 0; q < 30; q++ {
+			s, e := nodes[rnd.Intn(len(nodes))].ID, nodes[rnd.Intn(len(nodes))].ID
+
+			a := NewAstarWithH(g, s, e, Octile(g))
+			a.Search()
+			want, wantErr := a.PathToTarget()
+
+			p, err := h.FindPath(s, e)
+			if err != wantErr {
+				t.Fatalf("%d->%d got %v, want %v", s, e, err, wantErr)
+			}
+			if err != nil {
+				continue
+			}
+			if p.Cost < want.Cost-1e-9 {
+				t.Fatalf("%d->%d cost got %v, less than optimal %v", s, e, p.Cost, want.Cost)
+			}
+			if p.Nodes[0] != s || p.Nodes[len(p.Nodes)-1] != e {
+				t.Fatalf("%d->%d path got %v", s, e, p)
+			}
+			for _, pe := range p.Edges {
+				found := false
+				for _, ge := range g.EdgesFrom(pe.From) {
+					found = found || (ge.To == pe.To && ge.Cost == pe.Cost)
+				}
+				if !found {
+					t.Fatalf("%d->%d has no edge %v", s, e, pe)
+				}
+			}
+		}
+	}
+}
+
+func TestHPASetCost(t *testing.T) {
+	for _, opts := range []GridOptions{MovingAIOptions, {Corners: CutAlways}} {
+		rnd := rand.New(rand.NewSource(3))
+		gr := newTestRandomGrid(rnd, 30, 30, 0.15)
+		h := NewHPA(gr, opts, 6)
+
+		for i := 0; i < 60; i++ {
+			x, y := rnd.Intn(30), rnd.Intn(30)
+			cost := 0.0
+			if rnd.Intn(2) == 0 {
+				cost = 1 + float64(rnd.Intn(3))
+			}
+			h.SetCost(x, y, cost)
+		}
+
+		fresh := NewHPA(gr, opts, 6)
+		for q := 0; q < 50; q++ {
+			s, e := rnd.Intn(900), rnd.Intn(900)
+			p, err := h.FindPath(s, e)
+			want, wantErr := fresh.FindPath(s, e)
+			if err != wantErr || math.Abs(p.Cost-want.Cost) > 1e-9 {
+				t.Fatalf("rule %d %d->%d got %v %v, want %v %v", opts.Corners, s, e, p.Cost, err, want.Cost, wantErr)
+			}
+		}
+	}
+}
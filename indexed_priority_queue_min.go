@@ -1,4 +1,4 @@
-package main
+package graphalgo
 
 import (
 	"errors"
@@ -22,44 +22,44 @@ const (
 )
 
 // IndexedPriorityQueueMin is a min-heap.
-// data-item is index to graphNode.
+// data-item is a node ID.
 // compare policy is based on cost.
-type IndexedPriorityQueueMin struct {
-	cost                 map[int]float32 // key is index to graphNode, value is cost
-	nodeIndexToItemIndex map[int]int     // key is index to graphNode, value is index to data item
+type IndexedPriorityQueueMin[K comparable, W Weight] struct {
+	cost                 map[K]W   // key is node ID, value is cost
+	nodeIndexToItemIndex map[K]int // key is node ID, value is index to data item
 	way                  int
-	data                 []int
+	data                 []K
 	tail                 int // the index of the last item in data
 }
 
 // NewIndexedPriorityQueueMin returns an instance of IndexedPriorityQueueMin.
-func NewIndexedPriorityQueueMin(cost map[int]float32) *IndexedPriorityQueueMin {
+func NewIndexedPriorityQueueMin[K comparable, W Weight](cost map[K]W) *IndexedPriorityQueueMin[K, W] {
 	return NewIndexedPriorityQueueMinWithNWayAndSize(cost, 2, 1)
 }
 
 // NewIndexedPriorityQueueMinWithNWay returns an instance of IndexedPriorityQueueMin.
-func NewIndexedPriorityQueueMinWithNWay(cost map[int]float32, nWay int) *IndexedPriorityQueueMin {
+func NewIndexedPriorityQueueMinWithNWay[K comparable, W Weight](cost map[K]W, nWay int) *IndexedPriorityQueueMin[K, W] {
 	return NewIndexedPriorityQueueMinWithNWayAndSize(cost, nWay, 1)
 }
 
 // NewIndexedPriorityQueueMinWithSize returns an instance of IndexedPriorityQueueMin.
-func NewIndexedPriorityQueueMinWithSize(cost map[int]float32, s int) *IndexedPriorityQueueMin {
+func NewIndexedPriorityQueueMinWithSize[K comparable, W Weight](cost map[K]W, s int) *IndexedPriorityQueueMin[K, W] {
 	return NewIndexedPriorityQueueMinWithNWayAndSize(cost, 2, s)
 }
 
 // NewIndexedPriorityQueueMinWithNWayAndSize returns an instance of IndexedPriorityQueueMin with init-size.
-func NewIndexedPriorityQueueMinWithNWayAndSize(cost map[int]float32, nWay int, s int) *IndexedPriorityQueueMin {
-	return &IndexedPriorityQueueMin{
+func NewIndexedPriorityQueueMinWithNWayAndSize[K comparable, W Weight](cost map[K]W, nWay int, s int) *IndexedPriorityQueueMin[K, W] {
+	return &IndexedPriorityQueueMin[K, W]{
 		cost:                 cost,
-		nodeIndexToItemIndex: make(map[int]int, s),
+		nodeIndexToItemIndex: make(map[K]int, s),
 		way:                  nWay,
-		data:                 make([]int, s),
+		data:                 make([]K, s),
 		tail:                 invalidTail,
 	}
 }
 
 // isGreater returns true if cost to nodeIndexA is greater than cost to nodeIndexB, otherwise false.
-func (h *IndexedPriorityQueueMin) isGreater(nodeIndexA, nodeIndexB int) bool {
+func (h *IndexedPriorityQueueMin[K, W]) isGreater(nodeIndexA, nodeIndexB K) bool {
 	costA, ok := h.cost[nodeIndexA]
 	if !ok {
 		panic(ErrCostNotExist)
@@ -72,12 +72,27 @@ func (h *IndexedPriorityQueueMin) isGreater(nodeIndexA, nodeIndexB int) bool {
 }
 
 // IsEmpty returns true when heap is empty.
-func (h *IndexedPriorityQueueMin) IsEmpty() bool {
+func (h *IndexedPriorityQueueMin[K, W]) IsEmpty() bool {
 	return h.tail == invalidTail
 }
 
+// Size returns the number of items in heap.
+func (h *IndexedPriorityQueueMin[K, W]) Size() int {
+	return h.tail + 1
+}
+
+// Top returns the root node without removing it.
+// Top returns error when heap is empty.
+func (h *IndexedPriorityQueueMin[K, W]) Top() (K, error) {
+	if h.IsEmpty() {
+		var zero K
+		return zero, ErrEmptyHeap
+	}
+	return h.data[0], nil
+}
+
 // Insert inserts an item into heap.
-func (h *IndexedPriorityQueueMin) Insert(x int) {
+func (h *IndexedPriorityQueueMin[K, W]) Insert(x K) {
 	if h.tail+1 >= len(h.data) {
 		h.makeSpace()
 	}
@@ -88,14 +103,14 @@ func (h *IndexedPriorityQueueMin) Insert(x int) {
 	h.siftUp(h.tail)
 }
 
-func (h *IndexedPriorityQueueMin) makeSpace() {
-	d := make([]int, len(h.data)*2+1)
+func (h *IndexedPriorityQueueMin[K, W]) makeSpace() {
+	d := make([]K, len(h.data)*2+1)
 	copy(d, h.data)
 	h.data = d
 }
 
 // ChangePriority changes the priority of nodeIndex.
-func (h *IndexedPriorityQueueMin) ChangePriority(nodeIndex int) {
+func (h *IndexedPriorityQueueMin[K, W]) ChangePriority(nodeIndex K) {
 	itemIndex, ok := h.nodeIndexToItemIndex[nodeIndex]
 	if !ok {
 		panic(ErrNodeIndexNotExist)
@@ -113,9 +128,10 @@ func (h *IndexedPriorityQueueMin) ChangePriority(nodeIndex int) {
 // Pop removes the root node from the heap and returns that node.
 // Pop returns error when heap is empty. So you had better make sure
 // the heap is not empty before you invode Pop on it.
-func (h *IndexedPriorityQueueMin) Pop() (int, error) {
+func (h *IndexedPriorityQueueMin[K, W]) Pop() (K, error) {
 	if h.IsEmpty() {
-		return 0, ErrEmptyHeap
+		var zero K
+		return zero, ErrEmptyHeap
 	}
 
 	v := h.data[0]
@@ -129,7 +145,7 @@ func (h *IndexedPriorityQueueMin) Pop() (int, error) {
 	return v, nil
 }
 
-func (h *IndexedPriorityQueueMin) siftUp(begin int) (swap bool) {
+func (h *IndexedPriorityQueueMin[K, W]) siftUp(begin int) (swap bool) {
 	if h.IsEmpty() {
 		return
 	}
@@ -159,7 +175,7 @@ func (h *IndexedPriorityQueueMin) siftUp(begin int) (swap bool) {
 	}
 }
 
-func (h *IndexedPriorityQueueMin) siftDown(begin int) (swap bool) {
+func (h *IndexedPriorityQueueMin[K, W]) siftDown(begin int) (swap bool) {
 	if h.IsEmpty() {
 		return
 	}
@@ -174,7 +190,7 @@ func (h *IndexedPriorityQueueMin) siftDown(begin int) (swap bool) {
 	}
 }
 
-func (h *IndexedPriorityQueueMin) compareWithChildren(idx int) (newIdx int, swap bool) {
+func (h *IndexedPriorityQueueMin[K, W]) compareWithChildren(idx int) (newIdx int, swap bool) {
 	newIdx = idx
 	min := h.data[idx]
 
@@ -198,9 +214,9 @@ func (h *IndexedPriorityQueueMin) compareWithChildren(idx int) (newIdx int, swap
 	return
 }
 
-func (h *IndexedPriorityQueueMin) show() {
+func (h *IndexedPriorityQueueMin[K, W]) show() {
 	for i := 0; i <= h.tail; i++ {
-		fmt.Printf("%d ", h.data[i])
+		fmt.Printf("%v ", h.data[i])
 	}
 	fmt.Println()
 }
@@ -1,4 +1,4 @@
-package main
+package graphalgo
 
 import (
 	"testing"
@@ -19,10 +19,16 @@ func TestMaxIntBinaryHeap(t *testing.T) {
 		}
 
 		h := NewIndexedPriorityQueueMin(cost)
+		if _, err := h.Top(); err != ErrEmptyHeap {
+			t.Errorf("h.Top() of empty heap got %v, want %v", err, ErrEmptyHeap)
+		}
 		h.Insert(4)
 		if h.IsEmpty() {
 			t.Error("h should not be empty after Insert")
 		}
+		if i, _ := h.Top(); i != 4 || h.Size() != 1 {
+			t.Errorf("h.Top() got %d with size %d, want 4 with size 1", i, h.Size())
+		}
 
 		i, _ := h.Pop()
 		if i != 4 {
@@ -0,0 +1,455 @@
+package graphalgo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrGridNotUniform tells us a grid has walkable cells of different costs,
+// which Jump Point Search can not search.
+var ErrGridNotUniform = errors.New("grid not uniform")
+
+// Names of the grid searches. They search a Grid, not a Graph, so
+// NewSearcher does not know them, see NewJPS, NewJPSPlus and RunScenarios.
+const (
+	AlgorithmJPS     Algorithm = "jps"
+	AlgorithmJPSPlus Algorithm = "jps+"
+)
+
+// jpsDirs are the 8 moves on a grid, clockwise from east, y grows downward.
+// Odd ones are diagonal, and diagonal i is made of cardinal i-1 and i+1.
+var jpsDirs = [8][2]int{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+
+// JPS is Jump Point Search on the graph of a grid, whose walkable cells
+// all cost the same, with MovingAIOptions: 8 connectivity and no corner
+// cutting. It is Astar with Octile, which skips the nodes between jump
+// points, so it finds paths of the same cost with far fewer expansions.
+// The path is given cell by cell, as if it was found on gr.Graph.
+// With a JumpTable, it is JPS+, which looks the jumps up instead of
+// walking them.
+type JPS struct {
+	grid   *Grid
+	table  *JumpTable // nil for JPS
+	source int
+	target int
+
+	cellCost float64 // cost of every walkable cell, 0 if they differ
+
+	frontier map[int]Edge[int, float64] // search frontier, edges are jumps
+	gcost    map[int]float64            // cost to some node
+	fcost    map[int]float64            // gcost + octile distance to target
+	spt      map[int]Edge[int, float64] // shortest path tree of jump points
+	dir      map[int]int                // index in jpsDirs of the jump to a node, -1 for source
+
+	pq    *IndexedPriorityQueueMin[int, float64]
+	state StepState
+
+	err   error
+	stats SearchStats
+}
+
+// NewJPS returns an instance of JPS. s and t are node IDs of gr, see Grid.ID.
+// The costs of gr must not be changed while it is in use.
+func NewJPS(gr *Grid, s, t int) *JPS {
+	d := &JPS{grid: gr, cellCost: gr.uniformCost()}
+	d.Reset(s, t)
+	return d
+}
+
+// NewJPSPlus returns an instance of JPS+, which searches the grid of table.
+func NewJPSPlus(table *JumpTable, s, t int) *JPS {
+	d := NewJPS(table.grid, s, t)
+	d.table = table
+	return d
+}
+
+// uniformCost returns the cost of every walkable cell, or 0 if they differ.
+func (gr *Grid) uniformCost() float64 {
+	cost := 0.0
+	for y := 0; y < gr.Height; y++ {
+		for x := 0; x < gr.Width; x++ {
+			if !gr.Passable(x, y) {
+				continue
+			}
+			if c := gr.Cost(x, y); cost == 0 {
+				cost = c
+			} else if c != cost {
+				return 0
+			}
+		}
+	}
+	return cost
+}
+
+// Algorithm returns AlgorithmJPS, or AlgorithmJPSPlus with a JumpTable.
+func (d *JPS) Algorithm() Algorithm {
+	if d.table != nil {
+		return AlgorithmJPSPlus
+	}
+	return AlgorithmJPS
+}
+
+// Reset forgets the last search and sets a new query.
+func (d *JPS) Reset(s, t int) {
+	d.source = s
+	d.target = t
+	d.frontier = make(map[int]Edge[int, float64])
+	d.gcost = make(map[int]float64)
+	d.fcost = make(map[int]float64)
+	d.spt = make(map[int]Edge[int, float64])
+	d.dir = make(map[int]int)
+	d.pq = nil
+	d.state = StepSearching
+	d.err = nil
+	d.stats = SearchStats{}
+}
+
+// Stats returns what the last Search did. Expanded and Reached count
+// jump points only.
+func (d *JPS) Stats() SearchStats {
+	return d.stats
+}
+
+// Search trys to find the shortest path from source to target.
+func (d *JPS) Search() {
+	d.SearchContext(context.Background())
+}
+
+// SearchContext is Search which stops when ctx is done.
+func (d *JPS) SearchContext(ctx context.Context) {
+	for {
+		if err := checkContext(ctx); err != nil {
+			d.err = err
+			d.state = StepFailed
+			return
+		}
+		if d.Step(1) != StepSearching {
+			return
+		}
+	}
+}
+
+// Step expands at most maxExpansions jump points, then returns the state
+// of the search, see Astar.Step.
+func (d *JPS) Step(maxExpansions int) StepState {
+	if d.state != StepSearching {
+		return d.state
+	}
+
+	start := time.Now()
+	defer func() {
+		d.stats.Reached = len(d.frontier)
+		d.stats.Elapsed += time.Since(start)
+	}()
+
+	if d.pq == nil {
+		if d.cellCost == 0 {
+			d.err = ErrGridNotUniform
+			d.state = StepFailed
+			return d.state
+		}
+		if !d.grid.passableID(d.source) || !d.grid.passableID(d.target) {
+			d.err = ErrInvalidNodeIndex
+			d.state = StepFailed
+			return d.state
+		}
+
+		d.frontier[d.source] = Edge[int, float64]{From: d.source, To: d.source}
+		d.gcost[d.source] = 0
+		d.fcost[d.source] = 0
+		d.dir[d.source] = -1
+		d.pq = NewIndexedPriorityQueueMin(d.fcost)
+		d.pq.Insert(d.source)
+	}
+
+	for n := 0; maxExpansions <= 0 || n < maxExpansions; n++ {
+		if d.pq.IsEmpty() {
+			d.state = StepFailed
+			return d.state
+		}
+
+		idx, err := d.pq.Pop()
+		if err != nil {
+			d.err = err
+			d.state = StepFailed
+			return d.state
+		}
+
+		edge := d.frontier[idx]
+		d.spt[idx] = edge
+		d.stats.Expanded++
+		i := edge.To
+
+		if i == d.target {
+			d.state = StepFound
+			return d.state
+		}
+
+		d.successors(i, func(t, dir int) {
+			g := d.gcost[i] + d.distance(i, t)
+			if _, ok := d.frontier[t]; !ok {
+				d.frontier[t] = NewEdge(i, t, g-d.gcost[i])
+				d.gcost[t] = g
+				d.fcost[t] = g + d.distance(t, d.target)
+				d.dir[t] = dir
+				d.pq.Insert(t)
+			} else if g < d.gcost[t] {
+				if _, ok := d.spt[t]; !ok {
+					d.frontier[t] = NewEdge(i, t, g-d.gcost[i])
+					d.gcost[t] = g
+					d.fcost[t] = g + d.distance(t, d.target)
+					d.dir[t] = dir
+					d.pq.ChangePriority(t)
+				}
+			}
+		})
+	}
+
+	return d.state
+}
+
+// PathToTarget returns the shortest path from source to target, with
+// every cell between the jump points.
+func (d *JPS) PathToTarget() (Path[int, float64], error) {
+	if d.err != nil {
+		return Path[int, float64]{}, d.err
+	}
+	if d.state == StepSearching {
+		return Path[int, float64]{}, ErrSearchIncomplete
+	}
+	if _, ok := d.spt[d.target]; !ok {
+		return Path[int, float64]{}, ErrPathNotFound
+	}
+
+	var jumps []Edge[int, float64]
+	for idx := d.target; idx != d.source; {
+		e := d.spt[idx]
+		jumps = append(jumps, e)
+		idx = e.From
+	}
+
+	var path []Edge[int, float64]
+	for _, e := range reversePath(jumps) {
+		x, y := d.grid.XY(e.From)
+		tx, ty := d.grid.XY(e.To)
+		dx, dy := sign(tx-x), sign(ty-y)
+		for x != tx || y != ty {
+			cost := d.cellCost
+			if dx != 0 && dy != 0 {
+				cost *= math.Sqrt2
+			}
+			path = append(path, NewEdge(d.grid.ID(x, y), d.grid.ID(x+dx, y+dy), cost))
+			x, y = x+dx, y+dy
+		}
+	}
+
+	p := NewPath(d.source, path, d.Algorithm(), true)
+	p.Cost = d.gcost[d.target]
+	return p, nil
+}
+
+// distance returns the octile distance between two cells.
+func (d *JPS) distance(a, b int) float64 {
+	ax, ay := d.grid.XY(a)
+	bx, by := d.grid.XY(b)
+	dx, dy := math.Abs(float64(ax-bx)), math.Abs(float64(ay-by))
+	return (dx + dy + (math.Sqrt2-2)*math.Min(dx, dy)) * d.cellCost
+}
+
+// successors calls fn with every jump point from node id, and the
+// direction of the jump to it. Jumps go the way the search came to id,
+// or turn by 90 degrees at most, as in JPS+.
+func (d *JPS) successors(id int, fn func(t, dir int)) {
+	x, y := d.grid.XY(id)
+	from := d.dir[id]
+	for dir := range jpsDirs {
+		if from >= 0 {
+			turn := (dir - from + 8) % 8
+			if turn > 4 {
+				turn = 8 - turn
+			}
+			if turn > 2 || (from%2 == 1 && turn > 1) {
+				continue
+			}
+		}
+
+		var tx, ty int
+		var ok bool
+		if d.table != nil {
+			tx, ty, ok = d.table.jump(x, y, dir, d.target)
+		} else {
+			tx, ty, ok = d.jump(x, y, dir)
+		}
+		if ok {
+			fn(d.grid.ID(tx, ty), dir)
+		}
+	}
+}
+
+// jump walks from (x, y) in direction dir until it reaches the target or
+// a jump point, and returns it. The bool is false if it hits a wall first.
+func (d *JPS) jump(x, y, dir int) (int, int, bool) {
+	gr := d.grid
+	dx, dy := jpsDirs[dir][0], jpsDirs[dir][1]
+	tx, ty := gr.XY(d.target)
+	for {
+		if !gr.canStep(x, y, dx, dy) {
+			return 0, 0, false
+		}
+		x, y = x+dx, y+dy
+		if x == tx && y == ty {
+			return x, y, true
+		}
+
+		if dx != 0 && dy != 0 {
+			if _, _, ok := d.jump(x, y, dir-1); ok {
+				return x, y, true
+			}
+			if _, _, ok := d.jump(x, y, (dir+1)%8); ok {
+				return x, y, true
+			}
+		} else if gr.forced(x, y, dx, dy) {
+			return x, y, true
+		}
+	}
+}
+
+// canStep returns true if (x+dx, y+dy) can be walked to from (x, y)
+// without cutting corners.
+func (gr *Grid) canStep(x, y, dx, dy int) bool {
+	if !gr.Passable(x+dx, y+dy) {
+		return false
+	}
+	return dx == 0 || dy == 0 || (gr.Passable(x+dx, y) && gr.Passable(x, y+dy))
+}
+
+// forced returns true if (x, y), walked to straight by (dx, dy), has a
+// neighbor which can only be reached the shortest way through it.
+func (gr *Grid) forced(x, y, dx, dy int) bool {
+	if dx != 0 {
+		return (gr.Passable(x, y-1) && !gr.Passable(x-dx, y-1)) ||
+			(gr.Passable(x, y+1) && !gr.Passable(x-dx, y+1))
+	}
+	return (gr.Passable(x-1, y) && !gr.Passable(x-1, y-dy)) ||
+		(gr.Passable(x+1, y) && !gr.Passable(x+1, y-dy))
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// JumpTable is the jump distances of every cell of a grid in the 8
+// directions, which JPS+ looks up. It must be built again by NewJumpTable
+// when walkable cells of the grid change.
+type JumpTable struct {
+	grid *Grid
+	// dist is the number of steps to the next jump point in each direction,
+	// or 0 or less, minus the steps before a wall, if there is none.
+	dist [][8]int32
+}
+
+// NewJumpTable computes the jump distances of gr.
+func NewJumpTable(gr *Grid) *JumpTable {
+	t := &JumpTable{grid: gr, dist: make([][8]int32, gr.Width*gr.Height)}
+
+	// straight jumps depend on the next cell in the same row or column
+	for dir := 0; dir < 8; dir += 2 {
+		dx, dy := jpsDirs[dir][0], jpsDirs[dir][1]
+		t.sweep(dx, dy, func(x, y int) {
+			t.dist[gr.ID(x, y)][dir] = t.next(x, y, dir, gr.forced(x+dx, y+dy, dx, dy))
+		})
+	}
+	// diagonal ones depend on the straight ones of the next cell
+	for dir := 1; dir < 8; dir += 2 {
+		dx, dy := jpsDirs[dir][0], jpsDirs[dir][1]
+		t.sweep(dx, dy, func(x, y int) {
+			jump := false
+			if gr.canStep(x, y, dx, dy) {
+				next := t.dist[gr.ID(x+dx, y+dy)]
+				jump = next[dir-1] > 0 || next[(dir+1)%8] > 0
+			}
+			t.dist[gr.ID(x, y)][dir] = t.next(x, y, dir, jump)
+		})
+	}
+	return t
+}
+
+// sweep calls fn with every walkable cell, so that the cell next to it by
+// (dx, dy) comes first.
+func (t *JumpTable) sweep(dx, dy int, fn func(x, y int)) {
+	gr := t.grid
+	for j := 0; j < gr.Height; j++ {
+		y := j
+		if dy > 0 {
+			y = gr.Height - 1 - j
+		}
+		for i := 0; i < gr.Width; i++ {
+			x := i
+			if dx > 0 {
+				x = gr.Width - 1 - i
+			}
+			if gr.Passable(x, y) {
+				fn(x, y)
+			}
+		}
+	}
+}
+
+// next returns the jump distance of (x, y) in direction dir, jump tells
+// whether the next cell is a jump point.
+func (t *JumpTable) next(x, y, dir int, jump bool) int32 {
+	gr := t.grid
+	dx, dy := jpsDirs[dir][0], jpsDirs[dir][1]
+	if !gr.canStep(x, y, dx, dy) {
+		return 0
+	}
+	if jump {
+		return 1
+	}
+	n := t.dist[gr.ID(x+dx, y+dy)][dir]
+	if n > 0 {
+		return n + 1
+	}
+	return n - 1
+}
+
+// jump looks up the jump from (x, y) in direction dir. It stops on the
+// way if target is reached, or, for diagonal jumps, if target is straight
+// ahead from there.
+func (t *JumpTable) jump(x, y, dir, target int) (int, int, bool) {
+	gr := t.grid
+	dx, dy := jpsDirs[dir][0], jpsDirs[dir][1]
+	n := int(t.dist[gr.ID(x, y)][dir])
+	steps := n
+	if steps < 0 {
+		steps = -steps
+	}
+
+	tx, ty := gr.XY(target)
+	rx, ry := (tx-x)*dx, (ty-y)*dy // steps to the target row and column, negative if behind
+	switch {
+	case dy == 0 && ty == y && rx > 0 && rx <= steps:
+		return tx, ty, true
+	case dx == 0 && tx == x && ry > 0 && ry <= steps:
+		return tx, ty, true
+	case dx != 0 && dy != 0 && rx > 0 && ry > 0 && (rx <= steps || ry <= steps):
+		m := rx
+		if ry < m {
+			m = ry
+		}
+		return x + m*dx, y + m*dy, true
+	}
+
+	if n <= 0 {
+		return 0, 0, false
+	}
+	return x + n*dx, y + n*dy, true
+}
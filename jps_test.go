@@ -0,0 +1,88 @@
+package graphalgo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestJPS(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for round := 0; round < 20; round++ {
+		costs := make([][]float64, 24)
+		for y := range costs {
+			costs[y] = make([]float64, 32)
+			for x := range costs[y] {
+				if rnd.Float64() > 0.25 {
+					costs[y][x] = 1
+				}
+			}
+		}
+		gr, _ := GridFromCosts(costs)
+		g := gr.Graph(MovingAIOptions)
+		table := NewJumpTable(gr)
+
+		nodes := g.Nodes()
+		for q := 0; q < 20; q++ {
+			s, e := nodes[rnd.Intn(len(nodes))].ID, nodes[rnd.Intn(len(nodes))].ID
+
+			a := NewAstarWithH(g, s, e, Octile(g))
+			a.Search()
+			want, wantErr := a.PathToTarget()
+
+			for _, d := range []*JPS{NewJPS(gr, s, e), NewJPSPlus(table, s, e)} {
+				d.Search()
+				p, err := d.PathToTarget()
+				if err != wantErr {
+					t.Fatalf("%s %d->%d got %v, want %v", d.Algorithm(), s, e, err, wantErr)
+				}
+				if err != nil {
+					continue
+				}
+				if math.Abs(p.Cost-want.Cost) > 1e-9 {
+					t.Fatalf("%s %d->%d cost got %v, want %v", d.Algorithm(), s, e, p.Cost, want.Cost)
+				}
+				// the path is walkable on the graph
+				sum := 0.0
+				for _, pe := range p.Edges {
+					found := false
+					for _, ge := range g.EdgesFrom(pe.From) {
+						found = found || ge.To == pe.To
+					}
+					if !found {
+						t.Fatalf("%s %d->%d has no edge %v", d.Algorithm(), s, e, pe)
+					}
+					sum += pe.Cost
+				}
+				if math.Abs(sum-want.Cost) > 1e-9 {
+					t.Fatalf("%s %d->%d edges cost %v, want %v", d.Algorithm(), s, e, sum, want.Cost)
+				}
+			}
+		}
+	}
+}
+
+func TestJPSOpenGrid(t *testing.T) {
+	gr := NewGrid(64, 64)
+	s, e := gr.ID(0, 0), gr.ID(63, 40)
+
+	a := NewAstarWithH(gr.Graph(MovingAIOptions), s, e, Octile(gr.Graph(MovingAIOptions)))
+	a.Search()
+	for _, d := range []*JPS{NewJPS(gr, s, e), NewJPSPlus(NewJumpTable(gr), s, e)} {
+		d.Search()
+		p, err := d.PathToTarget()
+		if err != nil || p.Hops() != 63 || !p.Optimal {
+			t.Errorf("%s path got %v %v", d.Algorithm(), p, err)
+		}
+		if d.Stats().Expanded >= a.Stats().Expanded {
+			t.Errorf("%s expanded %d, astar %d", d.Algorithm(), d.Stats().Expanded, a.Stats().Expanded)
+		}
+	}
+
+	gr.SetCost(5, 5, 2)
+	d := NewJPS(gr, s, e)
+	d.Search()
+	if _, err := d.PathToTarget(); err != ErrGridNotUniform {
+		t.Errorf("non-uniform grid got %v, want %v", err, ErrGridNotUniform)
+	}
+}
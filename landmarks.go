@@ -0,0 +1,443 @@
+package graphalgo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math/rand"
+	"os"
+	"reflect"
+	"unsafe"
+)
+
+// LandmarkSelection tells NewLandmarks how to pick the landmarks.
+type LandmarkSelection int
+
+const (
+	// LandmarksAvoid picks every landmark in the region which the landmarks
+	// picked so far estimate worst, as the "avoid" method of Goldberg and
+	// Harrelson does.
+	LandmarksAvoid LandmarkSelection = iota
+	// LandmarksFarthest picks every landmark as far as it can be from the
+	// landmarks picked so far.
+	LandmarksFarthest
+	// LandmarksRandom picks random nodes.
+	LandmarksRandom
+)
+
+// LandmarkOptions tells NewLandmarks how many landmarks to pick and how.
+// Count 0 means 8. Seed seeds the random choices, so the same options
+// pick the same landmarks on the same graph.
+type LandmarkOptions struct {
+	Count     int
+	Selection LandmarkSelection
+	Seed      int64
+}
+
+// Landmarks is the preprocessing of ALT (A*, landmarks and triangle
+// inequality). For every landmark L it keeps the cost from L to every node
+// and from every node to L, computed by Dijkstra on the graph and on its
+// reverse graph. By the triangle inequality, d(v, t) >= d(L, t) - d(L, v)
+// and d(v, t) >= d(v, L) - d(t, L), which makes a heuristic much tighter
+// than the geometric ones on road-like graphs.
+//
+// The tables belong to the graph they are built on, and must be built
+// again when its edges change.
+type Landmarks[K comparable, W Weight] struct {
+	graph *Graph[K, W]
+	nodes []int // dense indices of the landmarks
+	from  [][]W // from[i][v] is the cost from landmark i to node v, Infinity if unreachable
+	to    [][]W // to[i][v] is the cost from node v to landmark i, Infinity if unreachable
+}
+
+// NewLandmarks picks landmarks of g and computes their cost tables.
+func NewLandmarks[K comparable, W Weight](g *Graph[K, W], opts LandmarkOptions) *Landmarks[K, W] {
+	l := &Landmarks[K, W]{graph: g}
+	n := g.NumNodes()
+	count := opts.Count
+	if count == 0 {
+		count = 8
+	}
+	if count > n {
+		count = n
+	}
+	rnd := rand.New(rand.NewSource(opts.Seed))
+	rg := g.Reverse()
+
+	picked := make(map[int]bool)
+	for len(l.nodes) < count {
+		var v int
+		switch opts.Selection {
+		case LandmarksFarthest:
+			v = l.farthest(rnd)
+		case LandmarksRandom:
+			v = rnd.Intn(n)
+		default:
+			v = l.avoid(rnd)
+		}
+		if picked[v] {
+			v = l.unpicked(rnd, picked)
+		}
+		picked[v] = true
+		l.add(v, rg)
+	}
+	return l
+}
+
+// add makes node idx a landmark.
+func (l *Landmarks[K, W]) add(idx int, rg *Graph[K, W]) {
+	l.nodes = append(l.nodes, idx)
+	l.from = append(l.from, l.costTable(l.graph, idx))
+	l.to = append(l.to, l.costTable(rg, idx))
+}
+
+// costTable returns the cost from node idx to every node of g, by dense index.
+func (l *Landmarks[K, W]) costTable(g *Graph[K, W], idx int) []W {
+	tree := landmarkTree(l.graph, g, idx)
+	table := make([]W, l.graph.NumNodes())
+	for i := range table {
+		n, _ := l.graph.Node(i)
+		table[i], _ = tree.Cost(n.ID) // Infinity if unreachable
+	}
+	return table
+}
+
+// landmarkTree returns the shortest path tree on g, which is base or its
+// reverse graph, of the node whose dense index in base is idx.
+func landmarkTree[K comparable, W Weight](base, g *Graph[K, W], idx int) *ShortestPathTree[K, W] {
+	n, _ := base.Node(idx)
+	d := NewDijkstraTree(g, n.ID, Infinity[W]())
+	d.Search()
+	return d.Tree()
+}
+
+// farthest returns the node which is the farthest from the landmarks, or
+// from a random node if there is none yet. Nodes no landmark reaches are
+// the farthest of all.
+func (l *Landmarks[K, W]) farthest(rnd *rand.Rand) int {
+	n := l.graph.NumNodes()
+	if len(l.nodes) == 0 {
+		tree := landmarkTree(l.graph, l.graph, rnd.Intn(n))
+		best, bestCost := 0, W(-1)
+		for i := 0; i < n; i++ {
+			nd, _ := l.graph.Node(i)
+			if c, ok := tree.Cost(nd.ID); ok && c > bestCost {
+				best, bestCost = i, c
+			}
+		}
+		return best
+	}
+
+	best, bestCost := 0, W(-1)
+	for v := 0; v < n; v++ {
+		c := Infinity[W]()
+		for i := range l.nodes {
+			if l.from[i][v] < c {
+				c = l.from[i][v]
+			}
+		}
+		if c > bestCost {
+			best, bestCost = v, c
+		}
+	}
+	return best
+}
+
+// avoid returns the landmark picked by the avoid method. It grows the
+// shortest path tree of a random root, and weights every node by how much
+// the landmarks so far underestimate the cost to it from the root. The
+// size of a node is the weight of its subtree, or 0 if a landmark is in
+// it. It starts at the node of the largest size and walks down to the
+// child of the largest size, until a leaf.
+func (l *Landmarks[K, W]) avoid(rnd *rand.Rand) int {
+	if len(l.nodes) == 0 {
+		return l.farthest(rnd)
+	}
+	root := rnd.Intn(l.graph.NumNodes())
+
+	tree := landmarkTree(l.graph, l.graph, root)
+	rn, _ := l.graph.Node(root)
+	h := l.Heuristic()
+
+	children := make(map[int][]int)
+	for _, id := range tree.Nodes() {
+		e, ok := tree.Pred(id)
+		if !ok {
+			continue
+		}
+		from, _ := l.graph.Index(e.From)
+		to, _ := l.graph.Index(id)
+		children[from] = append(children[from], to)
+	}
+
+	landmark := make(map[int]bool, len(l.nodes))
+	for _, v := range l.nodes {
+		landmark[v] = true
+	}
+	size := make(map[int]W)
+	covered := make(map[int]bool) // subtrees with a landmark in them
+	var walk func(v int)
+	walk = func(v int) {
+		nd, _ := l.graph.Node(v)
+		c, _ := tree.Cost(nd.ID)
+		s := c - h(rn.ID, nd.ID)
+		covered[v] = landmark[v]
+		for _, c := range children[v] {
+			walk(c)
+			s = AddWeight(s, size[c])
+			covered[v] = covered[v] || covered[c]
+		}
+		if covered[v] {
+			s = 0
+		}
+		size[v] = s
+	}
+	walk(root)
+
+	v := -1
+	for u, s := range size {
+		if s > 0 && (v < 0 || s > size[v] || (s == size[v] && u < v)) {
+			v = u
+		}
+	}
+	if v < 0 {
+		return l.farthest(rnd) // the landmarks estimate every node exactly
+	}
+	for len(children[v]) > 0 {
+		next := -1
+		for _, c := range children[v] {
+			if !covered[c] && (next < 0 || size[c] > size[next]) {
+				next = c
+			}
+		}
+		if next < 0 {
+			break
+		}
+		v = next
+	}
+	return v
+}
+
+// unpicked returns a random node which is not a landmark yet.
+func (l *Landmarks[K, W]) unpicked(rnd *rand.Rand, picked map[int]bool) int {
+	n := l.graph.NumNodes()
+	v := rnd.Intn(n)
+	for picked[v] {
+		v = (v + 1) % n
+	}
+	return v
+}
+
+// Nodes returns the landmarks.
+func (l *Landmarks[K, W]) Nodes() []K {
+	nodes := make([]K, len(l.nodes))
+	for i, idx := range l.nodes {
+		n, _ := l.graph.Node(idx)
+		nodes[i] = n.ID
+	}
+	return nodes
+}
+
+// Heuristic returns the ALT heuristic, the largest lower bound which the
+// landmarks give by the triangle inequality. It is consistent, so give it
+// to NewAstarWithH and call SetAdmissible(true).
+func (l *Landmarks[K, W]) Heuristic() func(nd1, nd2 K) W {
+	return func(nd1, nd2 K) W {
+		v, ok1 := l.graph.Index(nd1)
+		t, ok2 := l.graph.Index(nd2)
+		if !ok1 || !ok2 {
+			return 0
+		}
+
+		var h W
+		for i := range l.nodes {
+			from, to := l.from[i], l.to[i]
+			if !IsInfinity(from[t]) && !IsInfinity(from[v]) && from[t]-from[v] > h {
+				h = from[t] - from[v]
+			}
+			if !IsInfinity(to[v]) && !IsInfinity(to[t]) && to[v]-to[t] > h {
+				h = to[v] - to[t]
+			}
+		}
+		return h
+	}
+}
+
+// LandmarksFilename returns the name of the landmark file which goes next
+// to the graph file graphFilename.
+func LandmarksFilename(graphFilename string) string {
+	return graphFilename + ".alt"
+}
+
+// LandmarksVersion is the version of the landmark file format.
+const LandmarksVersion = 1
+
+// A landmark file is little-endian and looks like
+//
+//	magic     [8]byte  "GRAPHALT"
+//	version   uint32
+//	costKind  uint32   reflect.Kind of W
+//	costSize  uint32   size of W in bytes
+//	graph     uint32   fingerprint of the graph, see graphFingerprint
+//	nodes     uint64   n
+//	landmarks uint64   k
+//	indices   [k]uint32   dense index of each landmark
+//	from      [k][n]W
+//	to        [k][n]W
+//	checksum  uint32   CRC-32C of everything above
+//
+// Node IDs are not written, so the file is read back against the same
+// graph, whose nodes are in the same order. The fingerprint tells if it is.
+
+const landmarksMagic = "GRAPHALT"
+
+// StoreLandmarks writes l to the landmark file filename, see WriteLandmarks.
+func StoreLandmarks[K comparable, W Weight](filename string, l *Landmarks[K, W]) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	err = WriteLandmarks(f, l)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// WriteLandmarks writes the landmarks and cost tables of l to w.
+func WriteLandmarks[K comparable, W Weight](w io.Writer, l *Landmarks[K, W]) error {
+	if !hostLittleEndian {
+		return errBigEndian
+	}
+
+	crc := crc32.New(castagnoli)
+	bw := bufio.NewWriter(io.MultiWriter(w, crc))
+	put := func(v interface{}) {
+		binary.Write(bw, binary.LittleEndian, v)
+	}
+
+	bw.WriteString(landmarksMagic)
+	var zero W
+	put([4]uint32{LandmarksVersion, uint32(reflect.TypeOf(zero).Kind()), uint32(unsafe.Sizeof(zero)), graphFingerprint(l.graph)})
+	put([2]uint64{uint64(l.graph.NumNodes()), uint64(len(l.nodes))})
+	for _, idx := range l.nodes {
+		put(uint32(idx))
+	}
+	for _, tables := range [][][]W{l.from, l.to} {
+		for _, table := range tables {
+			bw.Write(costBytes(table))
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return binary.Write(w, binary.LittleEndian, crc.Sum32())
+}
+
+// LoadLandmarks reads the landmark file filename of g, see ReadLandmarks.
+func LoadLandmarks[K comparable, W Weight](filename string, g *Graph[K, W]) (*Landmarks[K, W], error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadLandmarks(f, g)
+}
+
+// ReadLandmarks reads the landmarks of g written by WriteLandmarks.
+// It returns ErrMapFormat if they are not of g, or of another cost type,
+// and ErrChecksum if they are corrupted.
+func ReadLandmarks[K comparable, W Weight](r io.Reader, g *Graph[K, W]) (*Landmarks[K, W], error) {
+	if !hostLittleEndian {
+		return nil, errBigEndian
+	}
+
+	crc := crc32.New(castagnoli)
+	br := io.TeeReader(bufio.NewReader(r), crc)
+	le := binary.LittleEndian
+
+	header := make([]byte, 40)
+	if _, err := io.ReadFull(br, header); err != nil || string(header[:8]) != landmarksMagic {
+		return nil, fmt.Errorf("%w: not a landmark file", ErrMapFormat)
+	}
+	if v := le.Uint32(header[8:]); v != LandmarksVersion {
+		return nil, fmt.Errorf("%w: landmark file version %d", ErrMapVersion, v)
+	}
+	var zero W
+	if reflect.Kind(le.Uint32(header[12:])) != reflect.TypeOf(zero).Kind() || uintptr(le.Uint32(header[16:])) != unsafe.Sizeof(zero) {
+		return nil, fmt.Errorf("%w: landmark costs are %v of %d bytes, want %T",
+			ErrMapFormat, reflect.Kind(le.Uint32(header[12:])), le.Uint32(header[16:]), zero)
+	}
+	n, k := le.Uint64(header[24:]), le.Uint64(header[32:])
+	if n != uint64(g.NumNodes()) {
+		return nil, fmt.Errorf("%w: landmarks of %d nodes, the graph has %d", ErrMapFormat, n, g.NumNodes())
+	}
+	if k > n {
+		return nil, fmt.Errorf("%w: %d landmarks of %d nodes", ErrMapFormat, k, n)
+	}
+	if le.Uint32(header[20:]) != graphFingerprint(g) {
+		return nil, fmt.Errorf("%w: landmarks of another graph", ErrMapFormat)
+	}
+
+	l := &Landmarks[K, W]{graph: g, nodes: make([]int, k)}
+	indices := make([]uint32, k)
+	if err := binary.Read(br, le, indices); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMapFormat, err)
+	}
+	for i, idx := range indices {
+		if uint64(idx) >= n {
+			return nil, fmt.Errorf("%w: landmark %d is not a node", ErrMapFormat, idx)
+		}
+		l.nodes[i] = int(idx)
+	}
+	for _, tables := range []*[][]W{&l.from, &l.to} {
+		*tables = make([][]W, k)
+		for i := range *tables {
+			table := make([]W, n)
+			if _, err := io.ReadFull(br, costBytes(table)); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrMapFormat, err)
+			}
+			(*tables)[i] = table
+		}
+	}
+
+	sum := crc.Sum32()
+	var want uint32
+	if err := binary.Read(br, le, &want); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMapFormat, err)
+	}
+	if sum != want {
+		return nil, ErrChecksum
+	}
+	return l, nil
+}
+
+// graphFingerprint returns the CRC-32C of the node IDs of g, and of the
+// targets and costs of its edges, in the order of their dense indices.
+func graphFingerprint[K comparable, W Weight](g *Graph[K, W]) uint32 {
+	crc := crc32.New(castagnoli)
+	bw := bufio.NewWriter(crc)
+	g.walkNodes(func(i int, n Node[K]) {
+		fmt.Fprintf(bw, "%v\n", n.ID)
+		for _, e := range g.edgesAt(i) {
+			to, _ := g.Index(e.To)
+			binary.Write(bw, binary.LittleEndian, uint32(to))
+			bw.Write(costBytes([]W{e.Cost}))
+		}
+	})
+	bw.Flush()
+	return crc.Sum32()
+}
+
+// costBytes returns the bytes of costs in memory.
+func costBytes[W Weight](costs []W) []byte {
+	if len(costs) == 0 {
+		return nil
+	}
+	var zero W
+	return unsafe.Slice((*byte)(unsafe.Pointer(&costs[0])), len(costs)*int(unsafe.Sizeof(zero)))
+}
@@ -0,0 +1,111 @@
+package graphalgo
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLandmarks(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	n := 200
+	g := newTestRandomGraph(rnd, n, n*4, 50, 1)
+
+	for _, sel := range []LandmarkSelection{LandmarksAvoid, LandmarksFarthest, LandmarksRandom} {
+		l := NewLandmarks(g, LandmarkOptions{Count: 6, Selection: sel, Seed: 7})
+		if len(l.Nodes()) != 6 {
+			t.Fatalf("selection %d picked %v", sel, l.Nodes())
+		}
+		h := l.Heuristic()
+
+		for q := 0; q < 30; q++ {
+			s, e := rnd.Intn(n), rnd.Intn(n)
+			d := NewDijkstra(g, s, e)
+			d.Search()
+			want, wantErr := d.PathToTarget()
+
+			a := NewAstarWithH(g, s, e, h)
+			a.SetAdmissible(true)
+			a.Search()
+			p, err := a.PathToTarget()
+			if err != wantErr {
+				t.Fatalf("selection %d %d->%d got %v, want %v", sel, s, e, err, wantErr)
+			}
+			if err != nil {
+				continue
+			}
+			if p.Cost != want.Cost {
+				t.Errorf("selection %d %d->%d got cost %v, want %v", sel, s, e, p.Cost, want.Cost)
+			}
+			if h(s, e) > want.Cost {
+				t.Errorf("selection %d h(%d, %d) = %v overestimates %v", sel, s, e, h(s, e), want.Cost)
+			}
+			if a.Stats().Expanded > d.Stats().Expanded {
+				t.Errorf("selection %d %d->%d expanded %d, Dijkstra %d", sel, s, e, a.Stats().Expanded, d.Stats().Expanded)
+			}
+		}
+	}
+}
+
+func TestLandmarksAvoid(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	differ := 0
+	for round := 0; round < 10; round++ {
+		g := newTestRandomGrid(rnd, 40, 40, 0.2).Graph(MovingAIOptions)
+		avoid := NewLandmarks(g, LandmarkOptions{Count: 6, Selection: LandmarksAvoid, Seed: int64(round)})
+		farthest := NewLandmarks(g, LandmarkOptions{Count: 6, Selection: LandmarksFarthest, Seed: int64(round)})
+		if !reflect.DeepEqual(avoid.Nodes(), farthest.Nodes()) {
+			differ++
+		}
+	}
+	if differ < 5 {
+		t.Errorf("avoid picked the landmarks of farthest on %d of 10 grids", 10-differ)
+	}
+}
+
+func TestLandmarksStore(t *testing.T) {
+	gr := NewGrid(20, 15)
+	for y := 2; y < 13; y++ {
+		gr.SetCost(10, y, 0)
+	}
+	g := gr.Graph(MovingAIOptions)
+	l := NewLandmarks(g, LandmarkOptions{Count: 4})
+
+	filename := LandmarksFilename(filepath.Join(t.TempDir(), "grid.bin"))
+	if err := StoreLandmarks(filename, l); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadLandmarks(filename, g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.nodes, l.nodes) || !reflect.DeepEqual(got.from, l.from) || !reflect.DeepEqual(got.to, l.to) {
+		t.Errorf("landmarks loaded differ from the ones stored")
+	}
+
+	var buf bytes.Buffer
+	WriteLandmarks(&buf, l)
+	data := append([]byte(nil), buf.Bytes()...)
+	data[len(data)/2] ^= 1
+	if _, err := ReadLandmarks(bytes.NewReader(data), g); err != ErrChecksum {
+		t.Errorf("corrupted landmarks got %v, want %v", err, ErrChecksum)
+	}
+
+	if _, err := ReadLandmarks(bytes.NewReader(buf.Bytes()), NewGrid(3, 3).Graph(MovingAIOptions)); !errors.Is(err, ErrMapFormat) {
+		t.Errorf("landmarks of another graph got %v, want %v", err, ErrMapFormat)
+	}
+	gr.SetCost(0, 0, 2)
+	other := gr.Graph(MovingAIOptions)
+	if other.NumNodes() != g.NumNodes() {
+		t.Fatalf("changed grid has %d nodes, want %d", other.NumNodes(), g.NumNodes())
+	}
+	if _, err := ReadLandmarks(bytes.NewReader(buf.Bytes()), other); !errors.Is(err, ErrMapFormat) {
+		t.Errorf("landmarks of a graph with other costs got %v, want %v", err, ErrMapFormat)
+	}
+	if _, err := ReadLandmarks(bytes.NewReader(buf.Bytes()), NewGraph[int, int64]()); !errors.Is(err, ErrMapFormat) {
+		t.Errorf("landmarks of another cost type got %v, want %v", err, ErrMapFormat)
+	}
+}
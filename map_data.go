@@ -1,46 +1,200 @@
-package main
+package graphalgo
 
 import (
+	"bufio"
 	"fmt"
 	"github.com/json-iterator/go"
+	"io"
 	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
 )
 
-type edge struct {
-	Cost float32 `json:"v"`
+// MapEdge is an edge in the .map file.
+type MapEdge struct {
+	Cost float64 `json:"v"`
 }
 
-type edges map[int]edge
+// MapEdges maps target node index to edge.
+type MapEdges map[int]MapEdge
 
-type edgesMap map[int]edges
+// EdgesMap maps source node index to its outgoing edges.
+type EdgesMap map[int]MapEdges
 
-type mapData struct {
-	edgesMap edgesMap
-}
+// Versions of the .map format.
+const (
+	MapVersionLegacy = 1 // {"from": {"to": {"v": cost}}}
+	MapVersion2      = 2 // header, node list and edge list, see map_data_v2.go
+)
 
-var p = fmt.Println
+// MapData is the content of a .map file.
+// Legacy files fill EdgesMap only. Version 2 files fill Directed,
+// Nodes and Edges.
+type MapData struct {
+	Version  int
+	Directed bool
+	Nodes    []MapNodeV2
+	Edges    []MapEdgeV2
+	EdgesMap EdgesMap
+}
 
-func (d *mapData) load(filename string) error {
+// Load reads the .map file filename.
+// The version of the file is detected by its "version" header,
+// files without it are legacy ones.
+func (d *MapData) Load(filename string) error {
 	content, err := ioutil.ReadFile(filename)
 	if err != nil {
-		p(err)
 		return err
 	}
+	return d.Decode(content)
+}
 
+// Decode parses the content of a .map file, see Load.
+func (d *MapData) Decode(content []byte) error {
 	var json = jsoniter.ConfigCompatibleWithStandardLibrary
-	err = json.Unmarshal(content, &d.edgesMap)
+
+	var header mapHeader
+	err := json.Unmarshal(content, &header)
 	if err != nil {
-		p(err)
 		return err
 	}
 
-	return nil
+	switch header.Version {
+	case 0, MapVersionLegacy:
+		*d = MapData{Version: MapVersionLegacy}
+		return json.Unmarshal(content, &d.EdgesMap)
+	case MapVersion2:
+		return d.decodeV2(content)
+	}
+	return ErrMapVersion
+}
+
+// Graph builds a directional graph from the map data.
+// Node IDs are the keys in the .map file. Nodes and edges are added in
+// the order of their keys, so the same data always builds the same graph.
+func (d *MapData) Graph() *Graph[int, float64] {
+	if d.Version == MapVersion2 {
+		return d.graphV2()
+	}
+
+	allIndex := sortedKeys(d.EdgesMap)
+
+	g := NewGraph[int, float64]()
+	for _, from := range allIndex {
+		g.AddNode(NewNode(from))
+	}
+	for _, from := range allIndex {
+		edges := d.EdgesMap[from]
+		for _, to := range sortedKeys(edges) {
+			g.AddEdge(NewEdge(from, to, edges[to].Cost))
+		}
+	}
+	return g
+}
+
+// Show prints the map data.
+func (d *MapData) Show() {
+	if d.Version == MapVersion2 {
+		fmt.Println(d.Directed, d.Nodes, d.Edges)
+		return
+	}
+	fmt.Println(d.EdgesMap)
+}
+
+// NewMapData returns the map data of g, which can be stored as a legacy .map file.
+// Positions, labels and attributes are lost, use NewMapDataV2 to keep them.
+// Every node is written as a key, so nodes without outgoing edges are kept.
+// The format has one edge from a node to another at most, if g has more,
+// the last one is kept.
+func NewMapData[W Weight](g *Graph[int, W]) *MapData {
+	d := &MapData{Version: MapVersionLegacy, EdgesMap: make(EdgesMap, g.NumNodes())}
+	g.walkNodes(func(i int, n Node[int]) {
+		edges := make(MapEdges)
+		for _, e := range g.edgesAt(i) {
+			edges[e.To] = MapEdge{Cost: float64(e.Cost)}
+		}
+		d.EdgesMap[n.ID] = edges
+	})
+	return d
 }
 
-func (d *mapData) show() {
-	p(d.edgesMap)
+// Store writes the map data to the .map file filename, in the format of
+// d.Version. Keys of legacy files are sorted by number, so the same data is always stored the same way.
+// If pretty is true, the JSON is indented like a.map.
+func (d *MapData) Store(filename string, pretty bool) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	err = d.Encode(f, pretty)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
 }
 
-func (d *mapData) store() {
+// Encode writes the map data as JSON to w, see Store.
+func (d *MapData) Encode(w io.Writer, pretty bool) error {
+	if d.Version == MapVersion2 {
+		return d.encodeV2(w, pretty)
+	}
 
+	bw := bufio.NewWriter(w)
+	indent := func(level int) {
+		if pretty {
+			bw.WriteByte('\n')
+			for i := 0; i < level; i++ {
+				bw.WriteString("    ")
+			}
+		}
+	}
+	colon := ":"
+	if pretty {
+		colon = ": "
+	}
+
+	bw.WriteByte('{')
+	for i, from := range sortedKeys(d.EdgesMap) {
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+		indent(1)
+		fmt.Fprintf(bw, "\"%d\"%s{", from, colon)
+
+		edges := d.EdgesMap[from]
+		for j, to := range sortedKeys(edges) {
+			if j > 0 {
+				bw.WriteByte(',')
+			}
+			indent(2)
+			fmt.Fprintf(bw, "\"%d\"%s{", to, colon)
+			indent(3)
+			fmt.Fprintf(bw, "\"v\"%s%s", colon, strconv.FormatFloat(edges[to].Cost, 'g', -1, 64))
+			indent(2)
+			bw.WriteByte('}')
+		}
+		if len(edges) > 0 {
+			indent(1)
+		}
+		bw.WriteByte('}')
+	}
+	if len(d.EdgesMap) > 0 {
+		indent(0)
+	}
+	bw.WriteByte('}')
+	if pretty {
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
 }
@@ -1,14 +1,127 @@
-package main
+package graphalgo
 
 import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
 	"testing"
 )
 
 // TestMapdata test loading a.map
 func TestMapdata(t *testing.T) {
-	md := mapData{}
-	err := md.load("../../bin/a.map")
+	md := MapData{}
+	err := md.Load("../../bin/a.map")
 	if err != nil {
 		t.Errorf("load a.map got error %v", err)
 	}
 }
+
+// TestMapdataStore tests that load, store then load again gets the same graph.
+func TestMapdataStore(t *testing.T) {
+	md := MapData{}
+	if err := md.Load("a.map"); err != nil {
+		t.Fatalf("load a.map got error %v", err)
+	}
+	g1 := md.Graph()
+	g1.AddNode(NewNode(100)) // isolated node
+
+	dir := t.TempDir()
+	for _, pretty := range []bool{false, true} {
+		name := filepath.Join(dir, "b.map")
+		if err := NewMapData(g1).Store(name, pretty); err != nil {
+			t.Fatalf("store got error %v", err)
+		}
+		first, _ := ioutil.ReadFile(name)
+
+		md2 := MapData{}
+		if err := md2.Load(name); err != nil {
+			t.Fatalf("load stored map got error %v", err)
+		}
+		g2 := md2.Graph()
+		if !reflect.DeepEqual(g1.Nodes(), g2.Nodes()) {
+			t.Errorf("nodes got %v, want %v", g2.Nodes(), g1.Nodes())
+		}
+		for _, n := range g1.Nodes() {
+			if !reflect.DeepEqual(g1.EdgesFrom(n.ID), g2.EdgesFrom(n.ID)) {
+				t.Errorf("edges from %d got %v, want %v", n.ID, g2.EdgesFrom(n.ID), g1.EdgesFrom(n.ID))
+			}
+		}
+
+		if err := NewMapData(g2).Store(name, pretty); err != nil {
+			t.Fatalf("store again got error %v", err)
+		}
+		second, _ := ioutil.ReadFile(name)
+		if !bytes.Equal(first, second) {
+			t.Errorf("stored twice got\n%s\nand\n%s", first, second)
+		}
+	}
+
+	var buf bytes.Buffer
+	md3 := MapData{EdgesMap: EdgesMap{10: MapEdges{2: MapEdge{Cost: 0.5}}, 2: MapEdges{}}}
+	md3.Encode(&buf, false)
+	if s := buf.String(); s != `{"2":{},"10":{"2":{"v":0.5}}}` {
+		t.Errorf("md3.Encode() got %s", s)
+	}
+}
+
+// TestMapdataV2 tests version 2 .map files.
+func TestMapdataV2(t *testing.T) {
+	md := MapData{}
+	if err := md.Load("a2.map"); err != nil {
+		t.Fatalf("load a2.map got error %v", err)
+	}
+	if md.Version != MapVersion2 || md.Directed {
+		t.Errorf("a2.map got version %d directed %v, want 2 false", md.Version, md.Directed)
+	}
+	g := md.Graph()
+	if len(g.EdgesFrom(0)) != 2 || len(g.EdgesFrom(5)) != 2 {
+		t.Errorf("a2.map should have edges both ways, got %v", g.EdgesFrom(0))
+	}
+
+	content := []byte(`{
+		"format": "graph-algo-map", "version": 2, "directed": true,
+		"nodes": [{"id": 7, "pos": [1, 2, 3], "label": "tower", "attrs": {"height": 30}}, {"id": 8}],
+		"edges": [{"from": 7, "to": 8, "cost": 2.5, "label": "stairs", "attrs": {"door": true}}]
+	}`)
+	if err := md.Decode(content); err != nil {
+		t.Fatal(err)
+	}
+	g = md.Graph()
+	n, _ := g.NodeByID(7)
+	if n.Pos != (Position{X: 1, Y: 2, Z: 3}) || n.Label != "tower" {
+		t.Errorf("node 7 got %v", n)
+	}
+	if h, _ := Attr[float64](n.Attrs, "height"); h != 30 {
+		t.Errorf("height of node 7 got %v, want 30", h)
+	}
+	if e := g.EdgesFrom(7); len(e) != 1 || e[0].Label != "stairs" || len(g.EdgesFrom(8)) != 0 {
+		t.Errorf("edges from 7 got %v", e)
+	}
+
+	var buf bytes.Buffer
+	if err := NewMapDataV2(g, true).Encode(&buf, false); err != nil {
+		t.Fatal(err)
+	}
+	md2 := MapData{}
+	if err := md2.Decode(buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(md2.Graph().Nodes(), g.Nodes()) || !reflect.DeepEqual(md2.Graph().EdgesFrom(7), g.EdgesFrom(7)) {
+		t.Errorf("round trip got %s", buf.String())
+	}
+
+	ring := MapData{}
+	ring.Load("a2.map")
+	undirected := NewMapDataV2(ring.Graph(), false)
+	if len(undirected.Edges) != 10 {
+		t.Errorf("non-directional a2.map should be stored with 10 edges, got %d", len(undirected.Edges))
+	}
+
+	if err := md.Decode([]byte(`{"version": 3}`)); err != ErrMapVersion {
+		t.Errorf("version 3 got %v, want %v", err, ErrMapVersion)
+	}
+	if err := md.Decode([]byte(`{"format": "other", "version": 2}`)); err != ErrMapFormat {
+		t.Errorf("format other got %v, want %v", err, ErrMapFormat)
+	}
+}
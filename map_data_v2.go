@@ -0,0 +1,176 @@
+package graphalgo
+
+import (
+	"errors"
+	"github.com/json-iterator/go"
+	"io"
+)
+
+// ErrMapVersion tells us the version of a .map file is not supported.
+var ErrMapVersion = errors.New("unsupported map version")
+
+// ErrMapFormat tells us a file is not a .map file of this package.
+var ErrMapFormat = errors.New("unknown map format")
+
+// MapFormat is the "format" header of version 2 .map files.
+const MapFormat = "graph-algo-map"
+
+// A version 2 .map file looks like
+//
+//	{
+//	    "format": "graph-algo-map",
+//	    "version": 2,
+//	    "directed": false,
+//	    "nodes": [
+//	        {"id": 0, "pos": [1.5, 2], "label": "gate", "attrs": {"team": "red"}},
+//	        {"id": 1}
+//	    ],
+//	    "edges": [
+//	        {"from": 0, "to": 1, "cost": 1.1, "label": "bridge"}
+//	    ]
+//	}
+//
+// Nodes which are only referred to by edges need not be listed.
+// If directed is false, every edge can be walked both ways.
+
+type mapHeader struct {
+	Format  string `json:"format,omitempty"`
+	Version int    `json:"version"`
+}
+
+type mapFileV2 struct {
+	mapHeader
+	Directed bool        `json:"directed"`
+	Nodes    []MapNodeV2 `json:"nodes"`
+	Edges    []MapEdgeV2 `json:"edges"`
+}
+
+// MapNodeV2 is a node in a version 2 .map file.
+// Pos has 2 or 3 numbers, or none if the node has no position.
+type MapNodeV2 struct {
+	ID    int       `json:"id"`
+	Pos   []float64 `json:"pos,omitempty"`
+	Label string    `json:"label,omitempty"`
+	Attrs Attrs     `json:"attrs,omitempty"`
+}
+
+// MapEdgeV2 is an edge in a version 2 .map file.
+type MapEdgeV2 struct {
+	From  int     `json:"from"`
+	To    int     `json:"to"`
+	Cost  float64 `json:"cost"`
+	Label string  `json:"label,omitempty"`
+	Attrs Attrs   `json:"attrs,omitempty"`
+}
+
+// NewMapDataV2 returns the map data of g, which can be stored as a version 2
+// .map file with positions, labels and attributes. If directed is false, g
+// must have every edge in both ways, and only the first way is written.
+func NewMapDataV2[W Weight](g *Graph[int, W], directed bool) *MapData {
+	d := &MapData{Version: MapVersion2, Directed: directed}
+
+	g.walkNodes(func(_ int, n Node[int]) {
+		mn := MapNodeV2{ID: n.ID, Label: n.Label, Attrs: n.Attrs}
+		if n.HasPos {
+			mn.Pos = []float64{n.Pos.X, n.Pos.Y}
+			if n.Pos.Z != 0 {
+				mn.Pos = append(mn.Pos, n.Pos.Z)
+			}
+		}
+		d.Nodes = append(d.Nodes, mn)
+	})
+
+	g.walkEdges(!directed, func(e Edge[int, W]) {
+		d.Edges = append(d.Edges, MapEdgeV2{
+			From:  e.From,
+			To:    e.To,
+			Cost:  float64(e.Cost),
+			Label: e.Label,
+			Attrs: e.Attrs,
+		})
+	})
+	return d
+}
+
+func (d *MapData) decodeV2(content []byte) error {
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+	var f mapFileV2
+	err := json.Unmarshal(content, &f)
+	if err != nil {
+		return err
+	}
+	if f.Format != "" && f.Format != MapFormat {
+		return ErrMapFormat
+	}
+
+	*d = MapData{
+		Version:  MapVersion2,
+		Directed: f.Directed,
+		Nodes:    f.Nodes,
+		Edges:    f.Edges,
+	}
+	return nil
+}
+
+func (d *MapData) encodeV2(w io.Writer, pretty bool) error {
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+	f := mapFileV2{
+		mapHeader: mapHeader{Format: MapFormat, Version: MapVersion2},
+		Directed:  d.Directed,
+		Nodes:     d.Nodes,
+		Edges:     d.Edges,
+	}
+	if f.Nodes == nil {
+		f.Nodes = []MapNodeV2{}
+	}
+	if f.Edges == nil {
+		f.Edges = []MapEdgeV2{}
+	}
+
+	var content []byte
+	var err error
+	if pretty {
+		content, err = json.MarshalIndent(f, "", "    ")
+		content = append(content, '\n')
+	} else {
+		content, err = json.Marshal(f)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(content)
+	return err
+}
+
+func (d *MapData) graphV2() *Graph[int, float64] {
+	g := NewGraph[int, float64]()
+	for _, mn := range d.Nodes {
+		n := NewNode(mn.ID)
+		n.Label = mn.Label
+		n.Attrs = mn.Attrs
+		if len(mn.Pos) >= 2 {
+			n.Pos = Position{X: mn.Pos[0], Y: mn.Pos[1]}
+			n.HasPos = true
+		}
+		if len(mn.Pos) >= 3 {
+			n.Pos.Z = mn.Pos[2]
+		}
+		g.AddNode(n)
+	}
+	for _, me := range d.Edges {
+		e := Edge[int, float64]{
+			From:  me.From,
+			To:    me.To,
+			Cost:  me.Cost,
+			Label: me.Label,
+			Attrs: me.Attrs,
+		}
+		g.AddEdge(e)
+		if !d.Directed {
+			g.AddEdge(e.Reversed())
+		}
+	}
+	return g
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package graphalgo
+
+import "os"
+
+// mmapFile reads filename into memory, where mmap is not supported.
+func mmapFile(filename string) ([]byte, func() error, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return nil }, nil
+}
@@ -0,0 +1,246 @@
+package graphalgo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MovingAITiles are the tiles of Moving AI Lab grid maps. '.' and 'G' are
+// ground, 'S' is swamp, which is walked like ground. '@' and 'O' are out of
+// bounds, 'T' is trees and 'W' is water, none of them can be walked.
+var MovingAITiles = Tiles{'.': 1, 'G': 1, 'S': 1}
+
+// MovingAIOptions are the moves of the Moving AI benchmarks: 8 connectivity,
+// diagonal moves cost sqrt(2) and never cut corners.
+var MovingAIOptions = GridOptions{Connectivity: Connect8, Corners: CutNever}
+
+// ReadMovingAIMap reads a Moving AI .map file, which looks like
+//
+//	type octile
+//	height 4
+//	width 6
+//	map
+//	@@@@@@
+//	@..T.@
+//	@....@
+//	@@@@@@
+func ReadMovingAIMap(r io.Reader) (*Grid, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+
+	width, height := -1, -1
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		if len(f) == 0 {
+			continue
+		}
+		if f[0] == "map" {
+			break
+		}
+		if len(f) != 2 {
+			return nil, fmt.Errorf("%w: movingai header %q", ErrMapFormat, sc.Text())
+		}
+		switch f[0] {
+		case "type":
+			if f[1] != "octile" {
+				return nil, fmt.Errorf("%w: movingai type %s", ErrMapFormat, f[1])
+			}
+		case "height":
+			height, _ = strconv.Atoi(f[1])
+		case "width":
+			width, _ = strconv.Atoi(f[1])
+		}
+	}
+	if width < 0 || height < 0 {
+		return nil, fmt.Errorf("%w: movingai map without width or height", ErrMapFormat)
+	}
+
+	rows := make([][]byte, 0, height)
+	for len(rows) < height && sc.Scan() {
+		row := []byte(strings.TrimRight(sc.Text(), "\r"))
+		if len(row) != width {
+			return nil, fmt.Errorf("%w: movingai row %d has %d cells, want %d", ErrMapFormat, len(rows), len(row), width)
+		}
+		rows = append(rows, row)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(rows) != height {
+		return nil, fmt.Errorf("%w: movingai map has %d rows, want %d", ErrMapFormat, len(rows), height)
+	}
+	return GridFromBytes(rows, MovingAITiles)
+}
+
+// LoadMovingAIMap reads the Moving AI .map file filename.
+func LoadMovingAIMap(filename string) (*Grid, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadMovingAIMap(f)
+}
+
+// Scenario is one query of a Moving AI .scen file.
+// Optimal is the cost of the shortest path from start to goal.
+type Scenario struct {
+	Bucket         int
+	Map            string
+	Width, Height  int
+	StartX, StartY int
+	GoalX, GoalY   int
+	Optimal        float64
+}
+
+// ReadScenarios reads a Moving AI .scen file, which has a "version 1" line
+// and then one scenario per line, separated by tabs.
+func ReadScenarios(r io.Reader) ([]Scenario, error) {
+	sc := bufio.NewScanner(r)
+	var scens []Scenario
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimRight(sc.Text(), "\r")
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "version") {
+			continue
+		}
+		f := strings.Split(text, "\t")
+		if len(f) != 9 {
+			f = strings.Fields(text)
+		}
+		if len(f) != 9 {
+			return nil, fmt.Errorf("%w: scen line %d has %d fields, want 9", ErrMapFormat, line, len(f))
+		}
+
+		var n [7]int
+		for i, j := range []int{0, 2, 3, 4, 5, 6, 7} { // f[1] is the map name
+			v, err := strconv.Atoi(f[j])
+			if err != nil {
+				return nil, fmt.Errorf("%w: scen line %d: %v", ErrMapFormat, line, err)
+			}
+			n[i] = v
+		}
+		opt, err := strconv.ParseFloat(f[8], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: scen line %d: %v", ErrMapFormat, line, err)
+		}
+		scens = append(scens, Scenario{
+			Bucket: n[0], Map: f[1],
+			Width: n[1], Height: n[2],
+			StartX: n[3], StartY: n[4],
+			GoalX: n[5], GoalY: n[6],
+			Optimal: opt,
+		})
+	}
+	return scens, sc.Err()
+}
+
+// LoadScenarios reads the Moving AI .scen file filename.
+func LoadScenarios(filename string) ([]Scenario, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadScenarios(f)
+}
+
+// ScenarioTolerance is how much the cost of a path may differ from the
+// optimal cost of its scenario, which is rounded in .scen files.
+const ScenarioTolerance = 1e-4
+
+// ScenarioResult is what one scenario got.
+// Mismatch is true if Err is not nil or Cost is not the optimal cost.
+type ScenarioResult struct {
+	Scenario Scenario
+	Cost     float64
+	Err      error
+	Stats    SearchStats
+	Mismatch bool
+}
+
+// ScenarioReport is what RunScenarios got.
+type ScenarioReport struct {
+	Algorithm  Algorithm
+	Results    []ScenarioResult
+	Mismatches int
+	Expanded   int
+	Elapsed    time.Duration // sum of the time spent in searches
+}
+
+// RunScenarios searches every scenario of scens on the graph of gr by
+// algorithm a, and compares the costs with the optimal ones. g must be
+// gr.Graph(MovingAIOptions) for the costs to match. Astar uses Octile.
+// AlgorithmJPS and AlgorithmJPSPlus search gr itself.
+func RunScenarios(gr *Grid, g *Graph[int, float64], scens []Scenario, a Algorithm) (*ScenarioReport, error) {
+	var s Searcher[int, float64]
+	switch a {
+	case AlgorithmJPS:
+		s = NewJPS(gr, 0, 0)
+	case AlgorithmJPSPlus:
+		s = NewJPSPlus(NewJumpTable(gr), 0, 0)
+	default:
+		var err error
+		s, err = NewSearcher(a, g, 0, 0, SearchOptions[int, float64]{Heuristic: Octile(g), Admissible: true})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rep := &ScenarioReport{Algorithm: a, Results: make([]ScenarioResult, 0, len(scens))}
+	for _, sc := range scens {
+		var bad error
+		switch {
+		case sc.Width != gr.Width || sc.Height != gr.Height:
+			bad = fmt.Errorf("%w: scenario is for a %dx%d map", ErrMapFormat, sc.Width, sc.Height)
+		case !gr.In(sc.StartX, sc.StartY) || !gr.In(sc.GoalX, sc.GoalY):
+			bad = fmt.Errorf("%w: scenario (%d,%d)->(%d,%d) is off the map", ErrMapFormat, sc.StartX, sc.StartY, sc.GoalX, sc.GoalY)
+		}
+		if bad != nil {
+			rep.add(ScenarioResult{Scenario: sc, Err: bad, Mismatch: true})
+			continue
+		}
+
+		s.Reset(gr.ID(sc.StartX, sc.StartY), gr.ID(sc.GoalX, sc.GoalY))
+		s.Search()
+
+		res := ScenarioResult{Scenario: sc, Stats: s.Stats()}
+		p, err := s.PathToTarget()
+		res.Cost, res.Err = p.Cost, err
+		res.Mismatch = err != nil || math.Abs(p.Cost-sc.Optimal) > ScenarioTolerance
+		rep.add(res)
+	}
+	return rep, nil
+}
+
+func (r *ScenarioReport) add(res ScenarioResult) {
+	r.Results = append(r.Results, res)
+	if res.Mismatch {
+		r.Mismatches++
+	}
+	r.Expanded += res.Stats.Expanded
+	r.Elapsed += res.Stats.Elapsed
+}
+
+// String returns the summary of r and a line for each mismatch.
+func (r *ScenarioReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %d scenarios, %d mismatches, %d expanded, %v",
+		r.Algorithm, len(r.Results), r.Mismatches, r.Expanded, r.Elapsed)
+	for _, res := range r.Results {
+		if !res.Mismatch {
+			continue
+		}
+		sc := res.Scenario
+		fmt.Fprintf(&b, "\n  (%d,%d)->(%d,%d) cost %g, want %g", sc.StartX, sc.StartY, sc.GoalX, sc.GoalY, res.Cost, sc.Optimal)
+		if res.Err != nil {
+			fmt.Fprintf(&b, ": %v", res.Err)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package graphalgo
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+const testMovingAIMap = `type octile
+height 4
+width 6
+map
+@@@@@@
+@..T.@
+@....@
+@@@@@@
+`
+
+const testScen = "version 1\n" +
+	"0\ttest.map\t6\t4\t1\t1\t4\t1\t4.41421356\n" +
+	"0\ttest.map\t6\t4\t1\t1\t2\t2\t1.41421356\n" +
+	"0\ttest.map\t6\t4\t1\t1\t4\t2\t3.00000000\n" + // wrong, it is 2+sqrt(2)
+	"0\ttest.map\t6\t4\t1\t1\t3\t1\t0\n" // trees
+
+func TestMovingAI(t *testing.T) {
+	gr, err := ReadMovingAIMap(strings.NewReader(testMovingAIMap))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gr.Width != 6 || gr.Height != 4 || gr.Passable(0, 0) || gr.Passable(3, 1) || !gr.Passable(4, 1) {
+		t.Fatalf("grid got %+v", gr)
+	}
+
+	scens, err := ReadScenarios(strings.NewReader(testScen))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scens) != 4 || scens[0] != (Scenario{Map: "test.map", Width: 6, Height: 4, StartX: 1, StartY: 1, GoalX: 4, GoalY: 1, Optimal: 4.41421356}) {
+		t.Fatalf("scenarios got %+v", scens)
+	}
+
+	g := gr.Graph(MovingAIOptions)
+	for _, a := range []Algorithm{AlgorithmAstar, AlgorithmDijkstra, AlgorithmJPS, AlgorithmJPSPlus} {
+		rep, err := RunScenarios(gr, g, scens, a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rep.Mismatches != 2 || !rep.Results[2].Mismatch || !rep.Results[3].Mismatch || rep.Results[3].Err == nil {
+			t.Errorf("%s report got %v", a, rep)
+		}
+		if c := rep.Results[2].Cost; math.Abs(c-(2+math.Sqrt2)) > 1e-9 {
+			t.Errorf("%s cost got %v, want %v", a, c, 2+math.Sqrt2)
+		}
+	}
+
+	off := []Scenario{
+		{Width: 6, Height: 4, StartX: 1, StartY: 1, GoalX: 6, GoalY: 1},
+		{Width: 6, Height: 4, StartX: 1, StartY: -1, GoalX: 4, GoalY: 1},
+	}
+	for _, a := range []Algorithm{AlgorithmAstar, AlgorithmJPS} {
+		rep, err := RunScenarios(gr, g, off, a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, res := range rep.Results {
+			if !errors.Is(res.Err, ErrMapFormat) || !res.Mismatch {
+				t.Errorf("%s scenario off the map got %v, want %v", a, res.Err, ErrMapFormat)
+			}
+		}
+	}
+
+	for _, sc := range [][]Scenario{scens, off, nil} {
+		if _, err := RunScenarios(gr, g, sc, "nope"); err != ErrUnknownAlgorithm {
+			t.Errorf("unknown algorithm on %d scenarios got %v, want %v", len(sc), err, ErrUnknownAlgorithm)
+		}
+	}
+}
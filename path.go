@@ -0,0 +1,155 @@
+package graphalgo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrPathNotJoined tells us two paths can not be concatenated,
+// because the first one does not end where the second one begins.
+var ErrPathNotJoined = errors.New("path not joined")
+
+// Algorithm names the search which produced a path.
+type Algorithm string
+
+// Names of the searches.
+const (
+	AlgorithmDFS        Algorithm = "dfs"
+	AlgorithmBFS        Algorithm = "bfs"
+	AlgorithmBiBFS      Algorithm = "bibfs"
+	AlgorithmDijkstra   Algorithm = "dijkstra"
+	AlgorithmAstar      Algorithm = "astar"
+	AlgorithmBiDijkstra Algorithm = "bidijkstra"
+	AlgorithmBiAstar    Algorithm = "biastar"
+)
+
+// Path is the result of a search.
+type Path[K comparable, W Weight] struct {
+	Nodes     []K          // ordered nodes, from source to target
+	Edges     []Edge[K, W] // ordered edges, len(Edges) == len(Nodes)-1
+	Cost      W            // total cost of Edges
+	Algorithm Algorithm    // the search which produced the path
+	Optimal   bool         // true if the path is proven to be the cheapest one
+}
+
+// NewPath returns the path which starts at source and goes along edges.
+func NewPath[K comparable, W Weight](source K, edges []Edge[K, W], a Algorithm, optimal bool) Path[K, W] {
+	p := Path[K, W]{
+		Nodes:     make([]K, 0, len(edges)+1),
+		Edges:     edges,
+		Algorithm: a,
+		Optimal:   optimal,
+	}
+	p.Nodes = append(p.Nodes, source)
+	for _, e := range edges {
+		p.Nodes = append(p.Nodes, e.To)
+		p.Cost = AddWeight(p.Cost, e.Cost)
+	}
+	return p
+}
+
+// Hops returns the number of edges in the path.
+func (p Path[K, W]) Hops() int {
+	return len(p.Edges)
+}
+
+// Source returns the first node of the path.
+func (p Path[K, W]) Source() (K, bool) {
+	if len(p.Nodes) == 0 {
+		var zero K
+		return zero, false
+	}
+	return p.Nodes[0], true
+}
+
+// Target returns the last node of the path.
+func (p Path[K, W]) Target() (K, bool) {
+	if len(p.Nodes) == 0 {
+		var zero K
+		return zero, false
+	}
+	return p.Nodes[len(p.Nodes)-1], true
+}
+
+// Concat returns p followed by q. q must begin where p ends.
+// The result is not optimal even if both of p and q are.
+func (p Path[K, W]) Concat(q Path[K, W]) (Path[K, W], error) {
+	if len(p.Nodes) == 0 {
+		return q, nil
+	}
+	if len(q.Nodes) == 0 {
+		return p, nil
+	}
+	if p.Nodes[len(p.Nodes)-1] != q.Nodes[0] {
+		return Path[K, W]{}, ErrPathNotJoined
+	}
+
+	edges := make([]Edge[K, W], 0, len(p.Edges)+len(q.Edges))
+	edges = append(edges, p.Edges...)
+	edges = append(edges, q.Edges...)
+
+	a := p.Algorithm
+	if a != q.Algorithm {
+		a = ""
+	}
+	return NewPath(p.Nodes[0], edges, a, false), nil
+}
+
+// Reverse returns the path walked backwards.
+// Every edge is turned around with its cost and attributes kept, so the result only
+// exists in the graph if the graph is non-directional.
+func (p Path[K, W]) Reverse() Path[K, W] {
+	q := Path[K, W]{
+		Nodes:     make([]K, len(p.Nodes)),
+		Edges:     make([]Edge[K, W], len(p.Edges)),
+		Cost:      p.Cost,
+		Algorithm: p.Algorithm,
+		Optimal:   p.Optimal,
+	}
+	for i, n := range p.Nodes {
+		q.Nodes[len(p.Nodes)-1-i] = n
+	}
+	for i, e := range p.Edges {
+		q.Edges[len(p.Edges)-1-i] = e.Reversed()
+	}
+	return q
+}
+
+// Compare compares p and q by cost, then by hops.
+// It returns -1 if p is better than q, 1 if q is better, otherwise 0.
+func (p Path[K, W]) Compare(q Path[K, W]) int {
+	switch {
+	case p.Cost < q.Cost:
+		return -1
+	case p.Cost > q.Cost:
+		return 1
+	case len(p.Edges) < len(q.Edges):
+		return -1
+	case len(p.Edges) > len(q.Edges):
+		return 1
+	}
+	return 0
+}
+
+// Equal returns true if p and q go through the same nodes with the same cost.
+func (p Path[K, W]) Equal(q Path[K, W]) bool {
+	if p.Cost != q.Cost || len(p.Nodes) != len(q.Nodes) {
+		return false
+	}
+	for i := range p.Nodes {
+		if p.Nodes[i] != q.Nodes[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the path like "a->b->c cost 3 (dijkstra)".
+func (p Path[K, W]) String() string {
+	nodes := make([]string, len(p.Nodes))
+	for i, n := range p.Nodes {
+		nodes[i] = fmt.Sprint(n)
+	}
+	return fmt.Sprintf("%s cost %v (%s)", strings.Join(nodes, "->"), p.Cost, p.Algorithm)
+}
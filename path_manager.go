@@ -0,0 +1,127 @@
+package graphalgo
+
+// PathRequestID identifies a request registered to a PathManager.
+type PathRequestID int
+
+// PathResult is what a PathManager sends when a request is finished.
+type PathResult[K comparable, W Weight] struct {
+	ID   PathRequestID
+	Path Path[K, W]
+	Err  error
+}
+
+type pathRequest[K comparable, W Weight] struct {
+	id       PathRequestID
+	searcher TimeSlicedSearcher[K, W]
+	done     func(PathResult[K, W])
+	ch       chan PathResult[K, W]
+}
+
+// PathManager runs many time-sliced searches, like the path manager in
+// "Programming Game AI by Example".
+// Every Update shares a fixed number of node expansions among all active
+// searches round-robin, one expansion at a time, and picks up where the
+// last Update stopped. When a search is finished, its requester is told
+// by callback or channel and the search is removed.
+//
+// PathManager is not safe for concurrent use, it is meant to be driven by
+// the game loop.
+type PathManager[K comparable, W Weight] struct {
+	budget   int // node expansions per Update
+	requests []*pathRequest[K, W]
+	cursor   int // the request to step next
+	nextID   PathRequestID
+}
+
+// NewPathManager returns a path manager which expands at most budget nodes per Update.
+func NewPathManager[K comparable, W Weight](budget int) *PathManager[K, W] {
+	return &PathManager[K, W]{
+		budget: budget,
+	}
+}
+
+// Register adds s to the manager. done is called in Update when s is finished.
+func (m *PathManager[K, W]) Register(s TimeSlicedSearcher[K, W], done func(PathResult[K, W])) PathRequestID {
+	return m.add(&pathRequest[K, W]{searcher: s, done: done})
+}
+
+// RegisterChan adds s to the manager. The returned channel receives the
+// result when s is finished, then it is closed.
+func (m *PathManager[K, W]) RegisterChan(s TimeSlicedSearcher[K, W]) (PathRequestID, <-chan PathResult[K, W]) {
+	ch := make(chan PathResult[K, W], 1)
+	id := m.add(&pathRequest[K, W]{searcher: s, ch: ch})
+	return id, ch
+}
+
+func (m *PathManager[K, W]) add(r *pathRequest[K, W]) PathRequestID {
+	m.nextID++
+	r.id = m.nextID
+	m.requests = append(m.requests, r)
+	return r.id
+}
+
+// Unregister removes request id without telling its requester,
+// except that the channel of RegisterChan is closed.
+// It returns false if id is not active.
+func (m *PathManager[K, W]) Unregister(id PathRequestID) bool {
+	for i, r := range m.requests {
+		if r.id == id {
+			m.remove(i)
+			if r.ch != nil {
+				close(r.ch)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Active returns the number of searches which are not finished.
+func (m *PathManager[K, W]) Active() int {
+	return len(m.requests)
+}
+
+// Update steps the active searches round-robin until the budget of this
+// update is used up or no search is active. It returns the number of
+// steps made.
+func (m *PathManager[K, W]) Update() int {
+	cycles := 0
+	for cycles < m.budget && len(m.requests) > 0 {
+		if m.cursor >= len(m.requests) {
+			m.cursor = 0
+		}
+
+		r := m.requests[m.cursor]
+		state := r.searcher.Step(1)
+		cycles++
+		if state == StepSearching {
+			m.cursor++
+			continue
+		}
+
+		m.remove(m.cursor)
+		m.notify(r)
+	}
+	return cycles
+}
+
+func (m *PathManager[K, W]) remove(i int) {
+	copy(m.requests[i:], m.requests[i+1:])
+	m.requests[len(m.requests)-1] = nil
+	m.requests = m.requests[:len(m.requests)-1]
+	if i < m.cursor {
+		m.cursor--
+	}
+}
+
+func (m *PathManager[K, W]) notify(r *pathRequest[K, W]) {
+	path, err := r.searcher.PathToTarget()
+	result := PathResult[K, W]{ID: r.id, Path: path, Err: err}
+	if r.done != nil {
+		r.done(result)
+	}
+	if r.ch != nil {
+		r.ch <- result
+		close(r.ch)
+	}
+}
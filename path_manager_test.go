@@ -0,0 +1,59 @@
+package graphalgo
+
+import (
+	"testing"
+)
+
+func TestPathManager(t *testing.T) {
+	g := newTestGraph()
+	m := NewPathManager[int, float64](2)
+
+	results := make(map[PathRequestID]PathResult[int, float64])
+	done := func(r PathResult[int, float64]) {
+		results[r.ID] = r
+	}
+
+	d := NewDijkstra(g, 0, 2)
+	a := NewAstar(g, 4, 2)
+	id1 := m.Register(d, done)
+	id2 := m.Register(a, done)
+	id3, ch := m.RegisterChan(NewDijkstra(g, 2, 0))
+	id4 := m.Register(NewDijkstra(g, 0, 1), done)
+	if !m.Unregister(id4) || m.Unregister(id4) {
+		t.Error("id4 should be unregistered once")
+	}
+
+	if n := m.Update(); n != 2 || m.Active() != 3 {
+		t.Errorf("m.Update() got %d steps, %d active, want 2 steps, 3 active", n, m.Active())
+	}
+	if d.Stats().Expanded != 1 || a.Stats().Expanded != 1 {
+		t.Errorf("the first update should step the first two searches once")
+	}
+	m.Update()
+	if d.Stats().Expanded != 2 {
+		t.Errorf("the second update should start with the third search")
+	}
+
+	for i := 0; m.Active() > 0; i++ {
+		if i > 100 {
+			t.Fatal("path manager never finished")
+		}
+		m.Update()
+	}
+
+	if r := results[id1]; r.Err != nil || r.Path.Hops() != 3 || r.Path.Nodes[1] != 5 {
+		t.Errorf("request 0->2 got %v %v", r.Path, r.Err)
+	}
+	if r := results[id2]; r.Err != nil || r.Path.Cost != 1.9+3.1 {
+		t.Errorf("request 4->2 got %v %v", r.Path, r.Err)
+	}
+	if r, ok := <-ch; !ok || r.ID != id3 || r.Err != ErrPathNotFound {
+		t.Errorf("request 2->0 got %v %v", r, ok)
+	}
+	if _, ok := <-ch; ok {
+		t.Error("channel should be closed")
+	}
+	if m.Update() != 0 {
+		t.Error("update without searches should do nothing")
+	}
+}
@@ -0,0 +1,60 @@
+package graphalgo
+
+import (
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	p := NewPath(1, []Edge[int, float64]{
+		NewEdge(1, 2, 1.5),
+		NewEdge(2, 3, 2.0),
+	}, AlgorithmDijkstra, true)
+	if p.Cost != 3.5 || p.Hops() != 2 || len(p.Nodes) != 3 {
+		t.Errorf("NewPath got %v, want 1->2->3 cost 3.5", p)
+	}
+	if s := p.String(); s != "1->2->3 cost 3.5 (dijkstra)" {
+		t.Errorf("p.String() got %q", s)
+	}
+
+	q := NewPath(3, []Edge[int, float64]{NewEdge(3, 4, 1.0)}, AlgorithmDijkstra, true)
+	pq, err := p.Concat(q)
+	if err != nil || pq.Cost != 4.5 || pq.Hops() != 3 || pq.Optimal {
+		t.Errorf("p.Concat(q) got %v %v, want 1->2->3->4 cost 4.5", pq, err)
+	}
+	if _, err := q.Concat(p); err != ErrPathNotJoined {
+		t.Errorf("q.Concat(p) got %v, want %v", err, ErrPathNotJoined)
+	}
+
+	r := p.Reverse()
+	if r.Nodes[0] != 3 || r.Edges[0].From != 3 || r.Edges[1].To != 1 || r.Cost != p.Cost {
+		t.Errorf("p.Reverse() got %v %v", r, r.Edges)
+	}
+	if !r.Reverse().Equal(p) {
+		t.Error("p.Reverse().Reverse() should equal p")
+	}
+
+	if p.Compare(pq) != -1 || pq.Compare(p) != 1 || p.Compare(p) != 0 {
+		t.Error("p should be better than pq")
+	}
+}
+
+// TestBiBFSPath tests that the path found by BiBFS keeps edge costs.
+func TestBiBFSPath(t *testing.T) {
+	g := NewGraph[int, float64]()
+	for i := 0; i < 6; i++ {
+		g.AddEdge(NewEdge(i, i+1, 1.5))
+		g.AddEdge(NewEdge(i+1, i, 1.5))
+	}
+
+	s := NewBiBFS(g, 0, 6)
+	s.Search()
+	p, err := s.PathToTarget()
+	if err != nil || p.Hops() != 6 || p.Cost != 9.0 {
+		t.Errorf("BiBFS 0->6 got %v %v, want 6 hops cost 9", p, err)
+	}
+	for i, e := range p.Edges {
+		if e.From != i || e.To != i+1 {
+			t.Errorf("BiBFS 0->6 edge %d got %v", i, e)
+		}
+	}
+}
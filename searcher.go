@@ -0,0 +1,155 @@
+package graphalgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnknownAlgorithm tells us no searcher is registered with the algorithm name.
+var ErrUnknownAlgorithm = errors.New("unknown algorithm")
+
+// ErrSearchIncomplete tells us a time-sliced search has not finished yet.
+var ErrSearchIncomplete = errors.New("search incomplete")
+
+// ErrSearchCanceled tells us a search was stopped by its context before it
+// finished. The error returned by PathToTarget also wraps the context error,
+// so errors.Is tells context.Canceled from context.DeadlineExceeded.
+var ErrSearchCanceled = errors.New("search canceled")
+
+// SearchStats is what a searcher did in its last search.
+type SearchStats struct {
+	Expanded int           // nodes whose outgoing edges were walked
+	Reached  int           // nodes which were put into the search frontier
+	Elapsed  time.Duration // time spent in Search
+}
+
+// Searcher is the common shape of all searches.
+//
+// A searcher is created for a graph and a query, then Search runs it and
+// PathToTarget returns the result. Reset changes the query, so a searcher
+// can be used again.
+type Searcher[K comparable, W Weight] interface {
+	// Algorithm returns the name of the search.
+	Algorithm() Algorithm
+	// Reset forgets the last search and sets a new query.
+	Reset(source, target K)
+	// Search trys to find a path from source to target.
+	Search()
+	// SearchContext is Search which stops when ctx is done.
+	// Stats tells what was done before it stopped.
+	SearchContext(ctx context.Context)
+	// PathToTarget returns the path found by Search.
+	PathToTarget() (Path[K, W], error)
+	// Stats returns what the last Search did.
+	Stats() SearchStats
+}
+
+// StepState is the state of a time-sliced search.
+type StepState int
+
+// States of a time-sliced search.
+const (
+	StepSearching StepState = iota // the search is not finished, call Step again
+	StepFound                      // the path to target is found
+	StepFailed                     // there is no path, or an error happened
+)
+
+// String returns the name of the state.
+func (s StepState) String() string {
+	switch s {
+	case StepSearching:
+		return "searching"
+	case StepFound:
+		return "found"
+	case StepFailed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+// TimeSlicedSearcher is a searcher which can run a few steps at a time,
+// so that many searches can share the time of a frame.
+// Dijkstra and Astar are time-sliced searchers.
+type TimeSlicedSearcher[K comparable, W Weight] interface {
+	Searcher[K, W]
+	// Step expands at most maxExpansions nodes, then returns the state of
+	// the search. maxExpansions <= 0 means no limit.
+	Step(maxExpansions int) StepState
+}
+
+// SearchOptions configures the searcher made by NewSearcher.
+type SearchOptions[K comparable, W Weight] struct {
+	// Heuristic is the estimated cost from nd1 to nd2.
+	// It is used by AlgorithmAstar and AlgorithmBiAstar only, nil means zero heuristic.
+	Heuristic func(nd1, nd2 K) W
+	// Admissible tells that Heuristic is consistent, see Astar.SetAdmissible.
+	Admissible bool
+	// Cost is the cost function of edges, nil means Edge.Cost.
+	// It is used by AlgorithmDijkstra and AlgorithmAstar only,
+	// see Dijkstra.SetCostFunc.
+	Cost func(e Edge[K, W]) (W, bool)
+}
+
+// NewSearcher returns the searcher named a for query source to target on g.
+func NewSearcher[K comparable, W Weight](a Algorithm, g *Graph[K, W], source, target K, opts SearchOptions[K, W]) (Searcher[K, W], error) {
+	switch a {
+	case AlgorithmDFS:
+		return NewDFS(g, source, target), nil
+	case AlgorithmBFS:
+		return NewBFS(g, source, target), nil
+	case AlgorithmBiBFS:
+		return NewBiBFS(g, source, target), nil
+	case AlgorithmDijkstra:
+		d := NewDijkstra(g, source, target)
+		d.SetCostFunc(opts.Cost)
+		return d, nil
+	case AlgorithmAstar:
+		if opts.Heuristic == nil {
+			a := NewAstar(g, source, target)
+			a.SetCostFunc(opts.Cost)
+			return a, nil
+		}
+		a := NewAstarWithH(g, source, target, opts.Heuristic)
+		a.SetAdmissible(opts.Admissible)
+		a.SetCostFunc(opts.Cost)
+		return a, nil
+	case AlgorithmBiDijkstra:
+		return NewBiDijkstra(g, source, target), nil
+	case AlgorithmBiAstar:
+		if opts.Heuristic == nil {
+			a := NewBiAstar(g, source, target, func(nd1, nd2 K) W { return 0 })
+			a.SetAdmissible(true)
+			return a, nil
+		}
+		a := NewBiAstar(g, source, target, opts.Heuristic)
+		a.SetAdmissible(opts.Admissible)
+		return a, nil
+	}
+	return nil, ErrUnknownAlgorithm
+}
+
+// Algorithms returns the names of all searches NewSearcher knows.
+// The grid searches AlgorithmJPS and AlgorithmJPSPlus are not among them.
+func Algorithms() []Algorithm {
+	return []Algorithm{
+		AlgorithmDFS,
+		AlgorithmBFS,
+		AlgorithmBiBFS,
+		AlgorithmDijkstra,
+		AlgorithmAstar,
+		AlgorithmBiDijkstra,
+		AlgorithmBiAstar,
+	}
+}
+
+// checkContext returns the error a search reports when ctx is done, otherwise nil.
+func checkContext(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrSearchCanceled, ctx.Err())
+	default:
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package graphalgo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func newTestGraph() *Graph[int, float64] {
+	md := MapData{EdgesMap: EdgesMap{
+		0: MapEdges{4: MapEdge{Cost: 2.9}, 5: MapEdge{Cost: 1.0}},
+		1: MapEdges{2: MapEdge{Cost: 3.1}},
+		2: MapEdges{4: MapEdge{Cost: 0.8}},
+		3: MapEdges{2: MapEdge{Cost: 3.7}},
+		4: MapEdges{1: MapEdge{Cost: 1.9}, 5: MapEdge{Cost: 3.0}},
+		5: MapEdges{3: MapEdge{Cost: 1.1}},
+	}}
+	return md.Graph()
+}
+
+// newTestRandomGraph returns a graph of n nodes with m random edges, whose
+// costs are in [0, maxCost). Node i has ID i*stride, so with a stride
+// other than 1 the IDs are not the dense indices.
+func newTestRandomGraph(rnd *rand.Rand, n, m int, maxCost int64, stride int) *Graph[int, int64] {
+	g := NewGraph[int, int64]()
+	for i := 0; i < n; i++ {
+		g.AddNode(NewNode(i * stride))
+	}
+	for i := 0; i < m; i++ {
+		g.AddEdge(NewEdge(rnd.Intn(n)*stride, rnd.Intn(n)*stride, rnd.Int63n(maxCost)))
+	}
+	return g
+}
+
+// testEdgeCases are queries on the graph 0->1->2 of newTestLineGraph, with
+// the error each gives: a path from a node to itself, which is empty, no
+// path, and a missing node.
+var testEdgeCases = []struct {
+	s, e int
+	err  error
+}{{1, 1, nil}, {2, 0, ErrPathNotFound}, {0, 9, ErrInvalidNodeIndex}}
+
+// newTestLineGraph returns the graph 0->1->2 which testEdgeCases query.
+func newTestLineGraph() *Graph[int, float64] {
+	g := NewGraph[int, float64]()
+	g.AddEdge(NewEdge(0, 1, 1.0))
+	g.AddEdge(NewEdge(1, 2, 1.0))
+	return g
+}
+
+// checkEdgeCases checks that find answers testEdgeCases.
+func checkEdgeCases(t *testing.T, find func(s, e int) (Path[int, float64], error)) {
+	t.Helper()
+	for _, c := range testEdgeCases {
+		p, err := find(c.s, c.e)
+		if err != c.err || (err == nil && p.Hops() != 0) {
+			t.Errorf("%d->%d got %v %v, want %v", c.s, c.e, p, err, c.err)
+		}
+	}
+}
+
+func newTestRandomGrid(rnd *rand.Rand, w, h int, blocked float64) *Grid {
+	gr := NewGrid(w, h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if rnd.Float64() < blocked {
+				gr.SetCost(x, y, 0)
+			}
+		}
+	}
+	return gr
+}
+
+// newTestGrid returns an 8-connected n*n grid, node ID is y*n+x.
+func newTestGrid(n int) *Graph[int, float64] {
+	g := NewGraph[int, float64]()
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			g.AddNode(NewNodeAt(y*n+x, float64(x), float64(y)))
+		}
+	}
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					tx, ty := x+dx, y+dy
+					if (dx == 0 && dy == 0) || tx < 0 || ty < 0 || tx >= n || ty >= n {
+						continue
+					}
+					cost := 1.0
+					if dx != 0 && dy != 0 {
+						cost = math.Sqrt2
+					}
+					g.AddEdge(NewEdge(y*n+x, ty*n+tx, cost))
+				}
+			}
+		}
+	}
+	return g
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// TestSearcher runs the same query through every searcher.
+func TestSearcher(t *testing.T) {
+	g := newTestGraph()
+
+	for _, a := range Algorithms() {
+		s, err := NewSearcher(a, g, 4, 2, SearchOptions[int, float64]{})
+		if err != nil {
+			t.Fatalf("NewSearcher(%s) got error %v", a, err)
+		}
+		if s.Algorithm() != a {
+			t.Errorf("s.Algorithm() got %s, want %s", s.Algorithm(), a)
+		}
+		if _, err := s.PathToTarget(); err != ErrSearchIncomplete {
+			t.Errorf("%s before Search got %v, want %v", a, err, ErrSearchIncomplete)
+		}
+
+		s.Search()
+		p, err := s.PathToTarget()
+		if err != nil {
+			t.Errorf("%s 4->2 got error %v", a, err)
+			continue
+		}
+		if p.Algorithm != a {
+			t.Errorf("%s 4->2 path algorithm got %s", a, p.Algorithm)
+		}
+		if n, _ := p.Target(); n != 2 {
+			t.Errorf("%s 4->2 got %v", a, p)
+		}
+		if s.Stats().Expanded == 0 {
+			t.Errorf("%s 4->2 expanded nothing", a)
+		}
+		if a == AlgorithmDijkstra || a == AlgorithmAstar || a == AlgorithmBiDijkstra || a == AlgorithmBiAstar {
+			if !p.Optimal || p.Cost != 1.9+3.1 {
+				t.Errorf("%s 4->2 got %v, want optimal cost 5", a, p)
+			}
+		}
+
+		s.Search() // again without Reset
+		if q, err := s.PathToTarget(); err != nil || q.Hops() != p.Hops() {
+			t.Errorf("%s 4->2 searched again got %v %v, want %v", a, q, err, p)
+		}
+
+		s.Reset(0, 0)
+		s.Search()
+		if p, err := s.PathToTarget(); err != nil || p.Hops() != 0 {
+			t.Errorf("%s 0->0 got %v %v, want empty path", a, p, err)
+		}
+
+		if a == AlgorithmBiBFS {
+			continue // BiBFS works on non-directional graph only
+		}
+		s.Reset(2, 0)
+		s.Search()
+		if _, err := s.PathToTarget(); err == nil {
+			t.Errorf("%s 2->0 should not be found", a)
+		}
+	}
+
+	for _, a := range []Algorithm{"nobody", AlgorithmJPS} {
+		if _, err := NewSearcher(a, g, 0, 1, SearchOptions[int, float64]{}); err != ErrUnknownAlgorithm {
+			t.Errorf("NewSearcher(%s) got %v, want %v", a, err, ErrUnknownAlgorithm)
+		}
+	}
+}
+
+// TestSearchContext tests that every searcher stops when its context is done.
+func TestSearchContext(t *testing.T) {
+	g := newTestGraph()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	for _, a := range Algorithms() {
+		s, _ := NewSearcher(a, g, 4, 2, SearchOptions[int, float64]{})
+
+		s.SearchContext(canceled)
+		_, err := s.PathToTarget()
+		if !errors.Is(err, ErrSearchCanceled) || !errors.Is(err, context.Canceled) {
+			t.Errorf("%s with canceled context got %v", a, err)
+		}
+
+		s.Reset(4, 2)
+		s.SearchContext(expired)
+		_, err = s.PathToTarget()
+		if !errors.Is(err, ErrSearchCanceled) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("%s with expired context got %v", a, err)
+		}
+
+		s.Reset(4, 2)
+		s.SearchContext(context.Background())
+		if _, err := s.PathToTarget(); err != nil {
+			t.Errorf("%s after Reset got %v", a, err)
+		}
+	}
+}
+
+// TestStep tests that time-sliced searches find the same path as Search.
+func TestStep(t *testing.T) {
+	g := newTestGraph()
+
+	searchers := []TimeSlicedSearcher[int, float64]{
+		NewDijkstra(g, 0, 2),
+		NewAstar(g, 0, 2),
+	}
+	for _, s := range searchers {
+		if _, err := s.PathToTarget(); err != ErrSearchIncomplete {
+			t.Errorf("%s before Step got %v, want %v", s.Algorithm(), err, ErrSearchIncomplete)
+		}
+
+		steps := 0
+		state := StepSearching
+		for state == StepSearching {
+			state = s.Step(1)
+			steps++
+		}
+		if state != StepFound {
+			t.Errorf("%s 0->2 got state %s, want %s", s.Algorithm(), state, StepFound)
+		}
+		if s.Stats().Expanded != steps {
+			t.Errorf("%s 0->2 expanded %d in %d steps", s.Algorithm(), s.Stats().Expanded, steps)
+		}
+		sliced, err := s.PathToTarget()
+		if err != nil {
+			t.Errorf("%s 0->2 got error %v", s.Algorithm(), err)
+		}
+		if s.Step(1) != StepFound {
+			t.Errorf("%s should stay found", s.Algorithm())
+		}
+
+		s.Reset(0, 2)
+		s.Search()
+		whole, _ := s.PathToTarget()
+		if !sliced.Equal(whole) {
+			t.Errorf("%s 0->2 got %v by Step, %v by Search", s.Algorithm(), sliced, whole)
+		}
+
+		s.Reset(2, 0)
+		if s.Step(0) != StepFailed {
+			t.Errorf("%s 2->0 should fail", s.Algorithm())
+		}
+	}
+}
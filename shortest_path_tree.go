@@ -0,0 +1,77 @@
+package graphalgo
+
+// ShortestPathTree is the shortest path tree which Dijkstra grows from its
+// source, see NewDijkstraTree and Dijkstra.Tree. It has every node the
+// search has expanded, with its cost from the source and the edge which
+// reaches it, so the path to any of them is read without searching again.
+type ShortestPathTree[K comparable, W Weight] struct {
+	graph  *Graph[K, W]
+	source K
+	cost   map[K]W          // cost of the nodes in spt, and of the frontier
+	spt    map[K]Edge[K, W] // key is an expanded node, value is the edge which reaches it
+}
+
+// Source returns the root of the tree.
+func (t *ShortestPathTree[K, W]) Source() K {
+	return t.source
+}
+
+// Len returns how many nodes are in the tree, with the source.
+func (t *ShortestPathTree[K, W]) Len() int {
+	return len(t.spt)
+}
+
+// Has returns true if node n is in the tree.
+func (t *ShortestPathTree[K, W]) Has(n K) bool {
+	_, ok := t.spt[n]
+	return ok
+}
+
+// Cost returns the cost of the shortest path from the source to node n.
+// The bool is false if n is not in the tree.
+func (t *ShortestPathTree[K, W]) Cost(n K) (W, bool) {
+	if !t.Has(n) {
+		return Infinity[W](), false
+	}
+	return t.cost[n], true
+}
+
+// Pred returns the edge which reaches node n in the tree.
+// The bool is false if n is the source or not in the tree.
+func (t *ShortestPathTree[K, W]) Pred(n K) (Edge[K, W], bool) {
+	if n == t.source {
+		return Edge[K, W]{}, false
+	}
+	e, ok := t.spt[n]
+	return e, ok
+}
+
+// Nodes returns the nodes in the tree, in the order of their dense indices.
+func (t *ShortestPathTree[K, W]) Nodes() []K {
+	var nodes []K
+	t.graph.walkNodes(func(_ int, n Node[K]) {
+		if t.Has(n.ID) {
+			nodes = append(nodes, n.ID)
+		}
+	})
+	return nodes
+}
+
+// PathTo returns the shortest path from the source to node n.
+// It returns ErrPathNotFound if n is not in the tree.
+func (t *ShortestPathTree[K, W]) PathTo(n K) (Path[K, W], error) {
+	if !t.Has(n) {
+		return Path[K, W]{}, ErrPathNotFound
+	}
+
+	var path []Edge[K, W]
+	for idx := n; idx != t.source; {
+		e := t.spt[idx]
+		path = append(path, e)
+		idx = e.From
+	}
+
+	p := NewPath(t.source, reversePath(path), AlgorithmDijkstra, true)
+	p.Cost = t.cost[n] // it differs from the sum of e.Cost with a cost function
+	return p, nil
+}
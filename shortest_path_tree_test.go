@@ -0,0 +1,89 @@
+package graphalgo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestShortestPathTree(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	g := NewGraph[int, int64]()
+	n := 80
+	for i := 0; i < n; i++ {
+		g.AddNode(NewNode(i))
+	}
+	for i := 0; i < n*3; i++ {
+		g.AddEdge(NewEdge(rnd.Intn(n), rnd.Intn(n), int64(rnd.Intn(20))))
+	}
+
+	d := NewDijkstraTree(g, 0, Infinity[int64]())
+	d.Search()
+	if _, err := d.PathToTarget(); err != nil {
+		t.Fatalf("tree search got %v", err)
+	}
+	tree := d.Tree()
+	if tree.Source() != 0 {
+		t.Errorf("tree source got %d, want 0", tree.Source())
+	}
+	if _, ok := tree.Pred(0); ok {
+		t.Error("source has a predecessor")
+	}
+
+	reached := 0
+	for v := 0; v < n; v++ {
+		dj := NewDijkstra(g, 0, v)
+		dj.Search()
+		want, wantErr := dj.PathToTarget()
+
+		p, err := tree.PathTo(v)
+		if err != wantErr {
+			t.Fatalf("0->%d got %v, want %v", v, err, wantErr)
+		}
+		if err != nil {
+			if tree.Has(v) {
+				t.Errorf("tree has %d which 0 can not reach", v)
+			}
+			continue
+		}
+		reached++
+		if c, ok := tree.Cost(v); !ok || c != want.Cost || p.Cost != want.Cost {
+			t.Errorf("0->%d got cost %v and path %v, want %v", v, c, p, want.Cost)
+		}
+		if e, ok := tree.Pred(v); v != 0 && (!ok || e.From != p.Edges[len(p.Edges)-1].From || e.To != v) {
+			t.Errorf("0->%d got pred %v %v, want the last edge of %v", v, e, ok, p)
+		}
+	}
+	if tree.Len() != reached || len(tree.Nodes()) != reached {
+		t.Errorf("tree has %d nodes, want %d", tree.Len(), reached)
+	}
+}
+
+func TestShortestPathTreeRadius(t *testing.T) {
+	gr := NewGrid(20, 20)
+	g := gr.Graph(GridOptions{Connectivity: Connect4})
+	s := gr.ID(10, 10)
+
+	d := NewDijkstraTree(g, s, 5)
+	for d.Step(7) == StepSearching {
+	}
+	tree := d.Tree()
+	for _, nd := range g.Nodes() {
+		x, y := gr.XY(nd.ID)
+		dist := abs(x-10) + abs(y-10)
+		if tree.Has(nd.ID) != (dist <= 5) {
+			t.Errorf("node (%d, %d) at %d in tree %v, radius 5", x, y, dist, tree.Has(nd.ID))
+		}
+	}
+	if _, err := tree.PathTo(gr.ID(0, 0)); err != ErrPathNotFound {
+		t.Errorf("path out of radius got %v, want %v", err, ErrPathNotFound)
+	}
+
+	d = NewDijkstraTree(g, -1, 5)
+	d.Search()
+	if _, err := d.PathToTarget(); err != ErrInvalidNodeIndex {
+		t.Errorf("tree of a missing node got %v, want %v", err, ErrInvalidNodeIndex)
+	}
+	if d.Tree().Len() != 0 {
+		t.Errorf("tree of a missing node has %d nodes", d.Tree().Len())
+	}
+}
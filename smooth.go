@@ -0,0 +1,62 @@
+package graphalgo
+
+// Path smoothing as in Mat Buckland's Programming Game AI by Example.
+// A path found on a graph of tiles zig-zags, smoothing joins edges whose
+// start can walk straight to the end of a later edge. walkable tells
+// whether one can walk straight from node from to node to, such as
+// Grid.LineOfSight. cost is the cost of such a walk, nil means the sum of
+// the costs of the edges it replaces. Joined edges lose labels and
+// attributes. The given path is not changed.
+
+// SmoothQuick joins every edge with the next one while the start of the
+// first can walk to the end of the next, and goes on from there.
+// It checks len(path) walks at most.
+func SmoothQuick[K comparable, W Weight](path []Edge[K, W], walkable func(from, to K) bool, cost func(from, to K) W) []Edge[K, W] {
+	if len(path) == 0 {
+		return nil
+	}
+
+	smoothed := []Edge[K, W]{path[0]}
+	for _, e := range path[1:] {
+		last := &smoothed[len(smoothed)-1]
+		if walkable(last.From, e.To) {
+			*last = joinEdges(*last, e, cost)
+		} else {
+			smoothed = append(smoothed, e)
+		}
+	}
+	return smoothed
+}
+
+// SmoothPrecise joins the edges from each edge to the farthest later edge
+// whose end its start can walk to. It removes more waypoints than
+// SmoothQuick, but checks len(path)^2 walks at most.
+func SmoothPrecise[K comparable, W Weight](path []Edge[K, W], walkable func(from, to K) bool, cost func(from, to K) W) []Edge[K, W] {
+	var smoothed []Edge[K, W]
+	for i := 0; i < len(path); i++ {
+		e := path[i]
+		for j := len(path) - 1; j > i; j-- {
+			if walkable(e.From, path[j].To) {
+				for _, next := range path[i+1 : j+1] {
+					e = joinEdges(e, next, nil)
+				}
+				if cost != nil {
+					e.Cost = cost(e.From, e.To)
+				}
+				i = j
+				break
+			}
+		}
+		smoothed = append(smoothed, e)
+	}
+	return smoothed
+}
+
+// joinEdges returns the edge from a.From to b.To.
+func joinEdges[K comparable, W Weight](a, b Edge[K, W], cost func(from, to K) W) Edge[K, W] {
+	c := AddWeight(a.Cost, b.Cost)
+	if cost != nil {
+		c = cost(a.From, b.To)
+	}
+	return NewEdge(a.From, b.To, c)
+}
@@ -0,0 +1,52 @@
+package graphalgo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSmooth(t *testing.T) {
+	gr, _ := ParseGrid(""+
+		"..........\n"+
+		"..........\n"+
+		"....##....\n"+
+		"....##....\n"+
+		"..........\n", nil)
+	g := gr.Graph(MovingAIOptions)
+	a := NewAstarWithH(g, gr.ID(0, 4), gr.ID(9, 0), Octile(g))
+	a.Search()
+	p, err := a.PathToTarget()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	quick := SmoothQuick(p.Edges, gr.LineOfSight, gr.SegmentCost)
+	precise := SmoothPrecise(p.Edges, gr.LineOfSight, gr.SegmentCost)
+	for _, s := range [][]Edge[int, float64]{quick, precise} {
+		if len(s) >= len(p.Edges) || s[0].From != p.Edges[0].From || s[len(s)-1].To != gr.ID(9, 0) {
+			t.Fatalf("smoothed got %v from %v", s, p.Edges)
+		}
+		sum := 0.0
+		for i, e := range s {
+			if !gr.LineOfSight(e.From, e.To) || (i > 0 && s[i-1].To != e.From) {
+				t.Errorf("smoothed edge %v is not walkable", e)
+			}
+			sum += e.Cost
+		}
+		if sum > p.Cost+1e-9 {
+			t.Errorf("smoothed cost %v, more than %v", sum, p.Cost)
+		}
+	}
+	if len(precise) > len(quick) {
+		t.Errorf("precise got %d edges, quick %d", len(precise), len(quick))
+	}
+
+	// without a cost function, joined edges cost the sum of theirs
+	s := SmoothQuick(p.Edges, func(from, to int) bool { return true }, nil)
+	if len(s) != 1 || math.Abs(s[0].Cost-p.Cost) > 1e-9 {
+		t.Errorf("smoothed got %v, want one edge of cost %v", s, p.Cost)
+	}
+	if SmoothPrecise[int, float64](nil, gr.LineOfSight, nil) != nil {
+		t.Error("smoothed empty path is not empty")
+	}
+}
@@ -0,0 +1,321 @@
+package graphalgo
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Names of the any-angle searches.
+const (
+	AlgorithmThetaStar     Algorithm = "theta*"
+	AlgorithmLazyThetaStar Algorithm = "lazy-theta*"
+)
+
+// ThetaStar is any-angle search on a grid. It is Astar on the cells with
+// MovingAIOptions, but a node may take the parent of its parent as its
+// own parent if it can see it, so paths go straight at any angle instead
+// of along the tiles. Lazy Theta* checks the line of sight only when a node
+// is expanded, which saves most of the checks.
+// Edges of the path join cells which see each other, they cost
+// Grid.SegmentCost. The path is short but not proven to be the shortest.
+//
+// ThetaStar does not take GridOptions: it always moves in 8 directions,
+// and neither moves nor lines of sight cut corners, as with CutNever. Its
+// costs are not those of Grid.Graph either, see SegmentCost, so they can
+// be compared only on grids whose walkable cells all cost the same.
+type ThetaStar struct {
+	grid   *Grid
+	lazy   bool
+	source int
+	target int
+
+	minCost float64 // the cheapest walkable cell, for the heuristic
+
+	frontier map[int]Edge[int, float64] // search frontier, From is the parent
+	gcost    map[int]float64            // cost to some node
+	fcost    map[int]float64            // gcost + straight-line cost to target
+	spt      map[int]Edge[int, float64] // shortest path tree
+
+	pq    *IndexedPriorityQueueMin[int, float64]
+	state StepState
+
+	err   error
+	stats SearchStats
+}
+
+// NewThetaStar returns an instance of Theta*. s and t are node IDs of gr.
+func NewThetaStar(gr *Grid, s, t int) *ThetaStar {
+	d := &ThetaStar{grid: gr, minCost: gr.minCost()}
+	d.Reset(s, t)
+	return d
+}
+
+// NewLazyThetaStar returns an instance of Lazy Theta*.
+func NewLazyThetaStar(gr *Grid, s, t int) *ThetaStar {
+	d := NewThetaStar(gr, s, t)
+	d.lazy = true
+	return d
+}
+
+// minCost returns the cost of the cheapest walkable cell, 0 if there is none.
+func (gr *Grid) minCost() float64 {
+	cost := 0.0
+	for y := 0; y < gr.Height; y++ {
+		for x := 0; x < gr.Width; x++ {
+			if c := gr.Cost(x, y); gr.Passable(x, y) && (cost == 0 || c < cost) {
+				cost = c
+			}
+		}
+	}
+	return cost
+}
+
+// Algorithm returns AlgorithmThetaStar or AlgorithmLazyThetaStar.
+func (d *ThetaStar) Algorithm() Algorithm {
+	if d.lazy {
+		return AlgorithmLazyThetaStar
+	}
+	return AlgorithmThetaStar
+}
+
+// Reset forgets the last search and sets a new query.
+func (d *ThetaStar) Reset(s, t int) {
+	d.source = s
+	d.target = t
+	d.frontier = make(map[int]Edge[int, float64])
+	d.gcost = make(map[int]float64)
+	d.fcost = make(map[int]float64)
+	d.spt = make(map[int]Edge[int, float64])
+	d.pq = nil
+	d.state = StepSearching
+	d.err = nil
+	d.stats = SearchStats{}
+}
+
+// Stats returns what the last Search did.
+func (d *ThetaStar) Stats() SearchStats {
+	return d.stats
+}
+
+// Search trys to find a path from source to target.
+func (d *ThetaStar) Search() {
+	d.SearchContext(context.Background())
+}
+
+// SearchContext is Search which stops when ctx is done.
+func (d *ThetaStar) SearchContext(ctx context.Context) {
+	for {
+		if err := checkContext(ctx); err != nil {
+			d.err = err
+			d.state = StepFailed
+			return
+		}
+		if d.Step(1) != StepSearching {
+			return
+		}
+	}
+}
+
+// Step expands at most maxExpansions nodes, then returns the state of the
+// search, see Astar.Step.
+func (d *ThetaStar) Step(maxExpansions int) StepState {
+	if d.state != StepSearching {
+		return d.state
+	}
+
+	start := time.Now()
+	defer func() {
+		d.stats.Reached = len(d.frontier)
+		d.stats.Elapsed += time.Since(start)
+	}()
+
+	gr := d.grid
+	if d.pq == nil {
+		if !gr.passableID(d.source) || !gr.passableID(d.target) {
+			d.err = ErrInvalidNodeIndex
+			d.state = StepFailed
+			return d.state
+		}
+
+		d.frontier[d.source] = Edge[int, float64]{From: d.source, To: d.source}
+		d.gcost[d.source] = 0
+		d.fcost[d.source] = 0
+		d.pq = NewIndexedPriorityQueueMin(d.fcost)
+		d.pq.Insert(d.source)
+	}
+
+	for n := 0; maxExpansions <= 0 || n < maxExpansions; n++ {
+		if d.pq.IsEmpty() {
+			d.state = StepFailed
+			return d.state
+		}
+
+		idx, err := d.pq.Pop()
+		if err != nil {
+			d.err = err
+			d.state = StepFailed
+			return d.state
+		}
+
+		if d.lazy {
+			d.setParent(idx)
+		}
+		edge := d.frontier[idx]
+		d.spt[idx] = edge
+		d.stats.Expanded++
+		i := edge.To
+
+		if i == d.target {
+			d.state = StepFound
+			return d.state
+		}
+
+		x, y := gr.XY(i)
+		gr.neighbors(x, y, MovingAIOptions, func(tx, ty int, dist float64) {
+			t := gr.ID(tx, ty)
+			if _, ok := d.spt[t]; ok {
+				return
+			}
+
+			// path 2, from the parent of i, if it can see t
+			from := i
+			if p := edge.From; p != i && (d.lazy || gr.LineOfSight(p, t)) {
+				from = p
+			}
+			g := d.gcost[from] + gr.SegmentCost(from, t)
+			if _, ok := d.frontier[t]; !ok {
+				d.frontier[t] = NewEdge(from, t, g-d.gcost[from])
+				d.gcost[t] = g
+				d.fcost[t] = g + d.heuristic(t)
+				d.pq.Insert(t)
+			} else if g < d.gcost[t] {
+				d.frontier[t] = NewEdge(from, t, g-d.gcost[from])
+				d.gcost[t] = g
+				d.fcost[t] = g + d.heuristic(t)
+				d.pq.ChangePriority(t)
+			}
+		})
+	}
+
+	return d.state
+}
+
+// setParent checks the line of sight of node id to the parent Lazy
+// Theta* assumed for it. If it can not see it, id takes the best expanded
+// neighbor as its parent instead.
+func (d *ThetaStar) setParent(id int) {
+	gr := d.grid
+	e := d.frontier[id]
+	if e.From == id || gr.LineOfSight(e.From, id) {
+		return
+	}
+
+	best := math.Inf(1)
+	x, y := gr.XY(id)
+	gr.neighbors(x, y, MovingAIOptions, func(tx, ty int, dist float64) {
+		n := gr.ID(tx, ty)
+		if _, ok := d.spt[n]; !ok {
+			return
+		}
+		if g := d.gcost[n] + gr.SegmentCost(n, id); g < best {
+			best = g
+			d.frontier[id] = NewEdge(n, id, gr.SegmentCost(n, id))
+			d.gcost[id] = g
+		}
+	})
+}
+
+func (d *ThetaStar) heuristic(id int) float64 {
+	ax, ay := d.grid.XY(id)
+	bx, by := d.grid.XY(d.target)
+	return math.Hypot(float64(ax-bx), float64(ay-by)) * d.minCost
+}
+
+// PathToTarget returns the path from source to target, whose edges join
+// cells which see each other.
+func (d *ThetaStar) PathToTarget() (Path[int, float64], error) {
+	if d.err != nil {
+		return Path[int, float64]{}, d.err
+	}
+	if d.state == StepSearching {
+		return Path[int, float64]{}, ErrSearchIncomplete
+	}
+	if _, ok := d.spt[d.target]; !ok {
+		return Path[int, float64]{}, ErrPathNotFound
+	}
+
+	var path []Edge[int, float64]
+	for idx := d.target; idx != d.source; {
+		e := d.spt[idx]
+		path = append(path, e)
+		idx = e.From
+	}
+
+	return NewPath(d.source, reversePath(path), d.Algorithm(), false), nil
+}
+
+// LineOfSight returns true if the straight line between the centers of
+// cells a and b only crosses walkable cells. A line through the corner
+// of cells needs both cells beside the corner walkable, as diagonal moves
+// without corner cutting do. It can be given to SmoothQuick and
+// SmoothPrecise.
+func (gr *Grid) LineOfSight(a, b int) bool {
+	ok := true
+	gr.walkLine(a, b, func(x, y int) bool {
+		ok = gr.Passable(x, y)
+		return ok
+	})
+	return ok
+}
+
+// SegmentCost returns the length of the straight line between the centers
+// of cells a and b, times the mean cost of the cells it crosses. Through
+// a corner, the line crosses the two cells beside it as well. So unlike
+// an edge of Grid.Graph, which costs the mean of its two cells, a diagonal
+// step costs the mean of four cells.
+func (gr *Grid) SegmentCost(a, b int) float64 {
+	sum, n := 0.0, 0
+	gr.walkLine(a, b, func(x, y int) bool {
+		if gr.In(x, y) {
+			sum += gr.Cost(x, y)
+			n++
+		}
+		return true
+	})
+	ax, ay := gr.XY(a)
+	bx, by := gr.XY(b)
+	return math.Hypot(float64(ax-bx), float64(ay-by)) * sum / float64(n)
+}
+
+// walkLine calls fn with every cell the line from the center of cell a to
+// the center of cell b crosses, both cells beside a corner it goes through
+// included, until fn returns false.
+func (gr *Grid) walkLine(a, b int, fn func(x, y int) bool) {
+	x, y := gr.XY(a)
+	x1, y1 := gr.XY(b)
+	dx, dy := x1-x, y1-y
+	sx, sy := sign(dx), sign(dy)
+	dx, dy = dx*sx, dy*sy
+	e := dx - dy
+	for n := 1 + dx + dy; n > 0; n-- {
+		if !fn(x, y) {
+			return
+		}
+		switch {
+		case e > 0:
+			x += sx
+			e -= 2 * dy
+		case e < 0:
+			y += sy
+			e += 2 * dx
+		case n > 1: // through a corner
+			if !fn(x+sx, y) || !fn(x, y+sy) {
+				return
+			}
+			x, y = x+sx, y+sy
+			e += 2*dx - 2*dy
+			n--
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package graphalgo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestLineOfSight(t *testing.T) {
+	gr, _ := ParseGrid(""+
+		".....\n"+
+		"..#..\n"+
+		".#...\n", nil)
+	cases := []struct {
+		ax, ay, bx, by int
+		want           bool
+	}{
+		{0, 0, 4, 0, true},
+		{0, 0, 4, 2, false}, // through (2, 1)
+		{0, 1, 2, 0, true},  // (1, 0) and (1, 1) are open
+		{0, 2, 2, 0, false}, // through the corner of (1, 1) and (1, 2)
+		{1, 1, 3, 1, false},
+		{3, 2, 4, 0, true},
+		{2, 2, 2, 2, true},
+	}
+	for _, c := range cases {
+		if got := gr.LineOfSight(gr.ID(c.ax, c.ay), gr.ID(c.bx, c.by)); got != c.want {
+			t.Errorf("(%d, %d)-(%d, %d) got %v, want %v", c.ax, c.ay, c.bx, c.by, got, c.want)
+		}
+		if got := gr.LineOfSight(gr.ID(c.bx, c.by), gr.ID(c.ax, c.ay)); got != c.want {
+			t.Errorf("(%d, %d)-(%d, %d) got %v, want %v", c.bx, c.by, c.ax, c.ay, got, c.want)
+		}
+	}
+}
+
+func TestSegmentCost(t *testing.T) {
+	gr := NewGrid(2, 2)
+	gr.SetCost(1, 0, 3)
+	cases := []struct {
+		a, b int
+		want float64
+	}{
+		{gr.ID(0, 0), gr.ID(1, 0), 2},                  // as Grid.Graph
+		{gr.ID(0, 0), gr.ID(1, 1), math.Sqrt2 * 6 / 4}, // (1, 0) and (0, 1) too
+		{gr.ID(0, 0), gr.ID(0, 0), 0},
+	}
+	for _, c := range cases {
+		if got := gr.SegmentCost(c.a, c.b); math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("%d-%d got %v, want %v", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestThetaStar(t *testing.T) {
+	gr := NewGrid(20, 20)
+	for _, d := range []*ThetaStar{NewThetaStar(gr, gr.ID(0, 0), gr.ID(19, 7)), NewLazyThetaStar(gr, gr.ID(0, 0), gr.ID(19, 7))} {
+		d.Search()
+		p, err := d.PathToTarget()
+		if err != nil || p.Hops() != 1 || math.Abs(p.Cost-math.Hypot(19, 7)) > 1e-9 {
+			t.Errorf("%s open grid got %v %v", d.Algorithm(), p, err)
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(4))
+	for round := 0; round < 10; round++ {
+		gr := newTestRandomGrid(rnd, 30, 30, 0.2)
+		g := gr.Graph(MovingAIOptions)
+		nodes := g.Nodes()
+		for q := 0; q < 20; q++ {
+			s, e := nodes[rnd.Intn(len(nodes))].ID, nodes[rnd.Intn(len(nodes))].ID
+			a := NewAstarWithH(g, s, e, Octile(g))
+			a.Search()
+			want, wantErr := a.PathToTarget()
+
+			for _, d := range []*ThetaStar{NewThetaStar(gr, s, e), NewLazyThetaStar(gr, s, e)} {
+				d.Search()
+				p, err := d.PathToTarget()
+				if err != wantErr {
+					t.Fatalf("%s %d->%d got %v, want %v", d.Algorithm(), s, e, err, wantErr)
+				}
+				if err != nil {
+					continue
+				}
+				// not proven, but any-angle paths are hardly longer than grid ones
+				if p.Cost > want.Cost*1.01 {
+					t.Errorf("%s %d->%d cost got %v, astar %v", d.Algorithm(), s, e, p.Cost, want.Cost)
+				}
+				for _, pe := range p.Edges {
+					if !gr.LineOfSight(pe.From, pe.To) {
+						t.Fatalf("%s %d->%d edge %v has no line of sight", d.Algorithm(), s, e, pe)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package graphalgo
+
+import (
+	"math"
+	"reflect"
+)
+
+// Weight is the type of edge cost.
+// Integer weights saturate at their max value, which is the infinity of them.
+// Float weights use +Inf as infinity.
+type Weight interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~float32 | ~float64
+}
+
+// Infinity returns the cost of an unreachable node for weight type W.
+// It is on the hot path of every search, so the predeclared types are
+// switched on without reflect.
+func Infinity[W Weight]() W {
+	var w W
+	var v int64
+	switch any(w).(type) {
+	case float64, float32:
+		return W(math.Inf(1))
+	case int8:
+		v = math.MaxInt8
+	case int16:
+		v = math.MaxInt16
+	case int32:
+		v = math.MaxInt32
+	case int:
+		v = math.MaxInt
+	case int64:
+		v = math.MaxInt64
+	default:
+		return namedInfinity[W]()
+	}
+	return W(v)
+}
+
+// namedInfinity is Infinity for named weight types, such as
+// "type Cost int32", whose kind is found by reflect.
+func namedInfinity[W Weight]() W {
+	var w W
+	switch reflect.TypeOf(w).Kind() {
+	case reflect.Float32, reflect.Float64:
+		return W(math.Inf(1))
+	case reflect.Int8:
+		v := int64(math.MaxInt8)
+		return W(v)
+	case reflect.Int16:
+		v := int64(math.MaxInt16)
+		return W(v)
+	case reflect.Int32:
+		v := int64(math.MaxInt32)
+		return W(v)
+	case reflect.Int:
+		v := int64(math.MaxInt)
+		return W(v)
+	default:
+		v := int64(math.MaxInt64)
+		return W(v)
+	}
+}
+
+// IsInfinity returns true if w is the infinity of W.
+func IsInfinity[W Weight](w W) bool {
+	return w >= Infinity[W]()
+}
+
+// AddWeight returns a+b. Costs are never negative, so the sum of integer
+// weights which overflows saturates at Infinity. Float weights overflow to
+// +Inf by themselves.
+func AddWeight[W Weight](a, b W) W {
+	s := a + b
+	if b > 0 && s < a {
+		return Infinity[W]()
+	}
+	return s
+}
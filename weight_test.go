@@ -0,0 +1,57 @@
+package graphalgo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWeight(t *testing.T) {
+	if v := Infinity[int8](); v != math.MaxInt8 {
+		t.Errorf("Infinity[int8]() got %d, want %d", v, math.MaxInt8)
+	}
+	if v := Infinity[int64](); v != math.MaxInt64 {
+		t.Errorf("Infinity[int64]() got %d, want %d", v, int64(math.MaxInt64))
+	}
+	if v := Infinity[float64](); !math.IsInf(v, 1) {
+		t.Errorf("Infinity[float64]() got %v, want +Inf", v)
+	}
+	type cost int16
+	if v := Infinity[cost](); v != math.MaxInt16 {
+		t.Errorf("Infinity[cost]() got %d, want %d", v, math.MaxInt16)
+	}
+
+	if v := AddWeight[int8](100, 100); v != math.MaxInt8 {
+		t.Errorf("AddWeight[int8](100, 100) got %d, want %d", v, math.MaxInt8)
+	}
+	if v := AddWeight(Infinity[int](), 1); !IsInfinity(v) {
+		t.Errorf("AddWeight(Infinity, 1) got %d, want Infinity", v)
+	}
+	if v := AddWeight[int32](3, 4); v != 7 {
+		t.Errorf("AddWeight[int32](3, 4) got %d, want %d", v, 7)
+	}
+}
+
+// TestDijkstraIntWeight tests searching on a graph with integer costs.
+func TestDijkstraIntWeight(t *testing.T) {
+	g := NewGraph[int, int64]()
+	g.AddEdge(NewEdge[int, int64](0, 1, 1))
+	g.AddEdge(NewEdge[int, int64](1, 2, 1))
+	g.AddEdge(NewEdge[int, int64](0, 2, 3))
+	g.AddEdge(NewEdge[int, int64](2, 3, math.MaxInt64))
+
+	d := NewDijkstra(g, 0, 2)
+	d.Search()
+	path, err := d.PathToTarget()
+	if err != nil || path.Hops() != 2 || path.Cost != 2 {
+		t.Errorf("Dijkstra 0->2 got %v %v, want 0->1->2", path, err)
+	}
+	if d.cost[2] != 2 {
+		t.Errorf("cost to 2 got %d, want %d", d.cost[2], 2)
+	}
+
+	d = NewDijkstra(g, 0, 3)
+	d.Search()
+	if !IsInfinity(d.cost[3]) {
+		t.Errorf("cost to 3 got %d, want Infinity", d.cost[3])
+	}
+}